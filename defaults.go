@@ -0,0 +1,80 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ApplyDefaults sets fields of the provided value (that has to be a pointer) to defaults from their 'default' tags, eg. `default:"8080"`. Defaults are parsed as inputs of Value.Set, so JSON is accepted for structs, maps and slices - for slices, a JSON list sets all elements at once. Fields that are already non-zero are left untouched, including fields set by a default of the struct they are within. Flag values are created as in Register (see WithFilters and WithJSONDecoder). Setting stops on the first error.
+func ApplyDefaults(base any, opts ...Option) error {
+	o := newOptions(opts)
+	for _, val := range Recursive(base, o.filters...) {
+		def, ok := lastTagLookup(val.Path(), "default")
+		if !ok {
+			continue
+		}
+		if o.decodeFn != nil {
+			val.SetJSONDecoder(o.decodeFn)
+		}
+		if err := val.setDefault(def); err != nil {
+			return fmt.Errorf("invalid default %q for field %s: %w", val.redact(def), JSONName(val.Path()), val.redactError(err))
+		}
+	}
+	return nil
+}
+
+// setDefault sets the value to the provided default, unless the value is already non-zero. The value is left zero on error.
+func (val *Value) setDefault(def string) error {
+	val.load()
+	if !val.isZero() {
+		return nil
+	}
+	v := elemIfPtr(val.get())
+	if val.isSlice && val.decodeFn == nil && val.separator == 0 && strings.HasPrefix(strings.TrimSpace(def), "[") {
+		raw := elemIfPtrType(elemIfPtrType(val.typ()).Elem()).Kind() == reflect.Interface
+		elems, err := splitJSONList(def, raw, val.unmarshalJSON)
+		if err != nil {
+			return err
+		}
+		for _, elem := range elems {
+			if err := val.setFn(val, elem); err != nil {
+				v.SetZero()
+				return err
+			}
+		}
+	} else if err := val.set(def, true); err != nil {
+		return err
+	}
+	val.store()
+	return nil
+}
+
+// isZero reports if the value is zero, including when any pointer leading to the value is nil. Unlike get, it does not allocate nil pointers.
+func (val *Value) isZero() bool {
+	v := val.base
+	for _, x := range val.fieldsIndexes {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return true
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestDefaultsServer struct {
+	Host string `json:"host" default:"localhost"`
+	Port int    `json:"port" default:"8080"`
+}
+
+type TestDefaultsBase struct {
+	Name     string               `json:"name" default:"app"`
+	Timeout  time.Duration        `json:"timeout" default:"5s"`
+	Verbose  *bool                `json:"verbose" default:"true"`
+	Tags     []string             `json:"tags" default:"[\"a\",\"b c\"]"`
+	Ports    []int                `json:"ports" default:"80" sep:","`
+	Limits   map[string]int       `json:"limits" default:"{\"cpu\":2}"`
+	Labels   map[string]string    `json:"labels" default:"env=dev,team=core"`
+	Server   TestDefaultsServer   `json:"server"`
+	Backup   TestDefaultsServer   `json:"backup" default:"{\"host\":\"backup\"}"`
+	Backends []TestDefaultsServer `json:"backends" default:"[{\"host\":\"a\"},{\"host\":\"b\"}]"`
+	Plain    string               `json:"plain"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		given *TestDefaultsBase
+		want  *TestDefaultsBase
+	}{
+		{
+			name:  "zero",
+			given: &TestDefaultsBase{},
+			want: &TestDefaultsBase{
+				Name:     "app",
+				Timeout:  5 * time.Second,
+				Verbose:  func() *bool { b := true; return &b }(),
+				Tags:     []string{"a", "b c"},
+				Ports:    []int{80},
+				Limits:   map[string]int{"cpu": 2},
+				Labels:   map[string]string{"env": "dev", "team": "core"},
+				Server:   TestDefaultsServer{Host: "localhost", Port: 8080},
+				Backup:   TestDefaultsServer{Host: "backup", Port: 8080},
+				Backends: []TestDefaultsServer{{Host: "a"}, {Host: "b"}},
+			},
+		},
+		{
+			name: "non-zero",
+			given: &TestDefaultsBase{
+				Name:     "given",
+				Verbose:  func() *bool { b := false; return &b }(),
+				Tags:     []string{"given"},
+				Server:   TestDefaultsServer{Port: 1},
+				Backup:   TestDefaultsServer{Port: 2},
+				Backends: []TestDefaultsServer{},
+			},
+			want: &TestDefaultsBase{
+				Name:     "given",
+				Timeout:  5 * time.Second,
+				Verbose:  func() *bool { b := false; return &b }(),
+				Tags:     []string{"given"},
+				Ports:    []int{80},
+				Limits:   map[string]int{"cpu": 2},
+				Labels:   map[string]string{"env": "dev", "team": "core"},
+				Server:   TestDefaultsServer{Host: "localhost", Port: 1},
+				Backup:   TestDefaultsServer{Host: "localhost", Port: 2},
+				Backends: []TestDefaultsServer{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require.NoError(t, jsonflag.ApplyDefaults(test.given))
+			require.Equal(t, test.want, test.given)
+		})
+	}
+}
+
+func TestApplyDefaultsError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		given     any
+		wantError string
+	}{
+		{
+			name: "int",
+			given: &struct {
+				Port int `json:"port" default:"http"`
+			}{},
+			wantError: `invalid default "http" for field port: strconv.ParseInt: parsing "http": invalid syntax`,
+		},
+		{
+			name: "slice",
+			given: &struct {
+				Ports []int `json:"ports" default:"[1,\"x\"]"`
+			}{},
+			wantError: `invalid default "[1,\"x\"]" for field ports: strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+		{
+			name: "secret",
+			given: &struct {
+				Port int `json:"port" default:"http" secret:"true"`
+			}{},
+			wantError: `invalid default "***" for field port: jsonflag: invalid secret value, want int`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require.EqualError(t, jsonflag.ApplyDefaults(test.given), test.wantError)
+		})
+	}
+}
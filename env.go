@@ -0,0 +1,62 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"fmt"
+)
+
+// WithLookupEnv sets the function used to look up environment variables, eg. to provide a fixed environment in tests. By default os.LookupEnv is used.
+func WithLookupEnv(fn func(string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookupEnv = fn
+	}
+}
+
+// ApplyEnv sets the provided flag values from environment variables. Variables names are created from flag names (see WithName), converted with EnvCase (regardless of WithCase) and joined with the provided prefix, eg. "APP_URL_HOST" for prefix "APP" and path "url.host". Names can be overridden with 'env' tag, holding the complete variable name (the prefix is not added), or set to "-" to ignore the field. The base value is ignored. References are resolved as in Register (see WithReferences). Setting stops on the first error.
+func ApplyEnv(values []*Value, prefix string, opts ...Option) error {
+	o := newOptions(opts)
+	o.setResolvers(values)
+	for _, val := range values {
+		name := envName(o, val, prefix)
+		if name == "" {
+			continue
+		}
+		s, ok := o.lookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := val.setFrom(Source{Kind: SourceEnv, Name: name, Input: s}, s); err != nil {
+			return fmt.Errorf("environment variable %s for flag %s: %w", name, o.name(val), val.redactError(err))
+		}
+	}
+	return nil
+}
+
+func envName(o *options, val *Value, prefix string) string {
+	if len(val.Path()) == 0 { // the base value
+		return ""
+	}
+	if tag := lastTag(val.Path(), "env"); tag == "-" {
+		return ""
+	} else if tag != "" {
+		return tag
+	}
+	name := EnvCase(o.nameFn(val.Path()))
+	if name == "" || prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestEnvBase struct {
+	URL struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	} `json:"url"`
+	MaxRetries int    `json:"maxRetries"`
+	Token      string `json:"token" env:"API_TOKEN"`
+	Internal   string `json:"internal" env:"-"`
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		prefix    string
+		opts      []jsonflag.Option
+		env       map[string]string
+		wantError string
+		want      *TestEnvBase
+	}{
+		{
+			name:   "prefix",
+			prefix: "APP",
+			env:    map[string]string{"APP_URL_HOST": "example.com", "APP_URL_PORT": "8080", "APP_MAX_RETRIES": "3", "URL_HOST": "ignored"},
+			want: func() *TestEnvBase {
+				b := &TestEnvBase{MaxRetries: 3}
+				b.URL.Host, b.URL.Port = "example.com", 8080
+				return b
+			}(),
+		},
+		{
+			name: "no-prefix",
+			env:  map[string]string{"URL_HOST": "example.com"},
+			want: func() *TestEnvBase {
+				b := &TestEnvBase{}
+				b.URL.Host = "example.com"
+				return b
+			}(),
+		},
+		{
+			name:   "env-tag",
+			prefix: "APP",
+			env:    map[string]string{"API_TOKEN": "secret", "APP_TOKEN": "ignored", "APP_INTERNAL": "ignored"},
+			want:   &TestEnvBase{Token: "secret"},
+		},
+		{
+			name:   "base-value",
+			prefix: "APP",
+			env:    map[string]string{"APP_INPUT": `{"maxRetries":5}`, "INPUT": `{"maxRetries":5}`},
+			want:   &TestEnvBase{},
+		},
+		{
+			name: "base-value-no-prefix",
+			env:  map[string]string{"INPUT": `{"maxRetries":5}`},
+			want: &TestEnvBase{},
+		},
+		{
+			name:   "name-function",
+			prefix: "APP",
+			opts:   []jsonflag.Option{jsonflag.WithName(jsonflag.Name)},
+			env:    map[string]string{"APP_URL_HOST": "example.com", "APP_MAX_RETRIES": "3"},
+			want: func() *TestEnvBase {
+				b := &TestEnvBase{MaxRetries: 3}
+				b.URL.Host = "example.com"
+				return b
+			}(),
+		},
+		{
+			name:   "case-function",
+			prefix: "APP",
+			opts:   []jsonflag.Option{jsonflag.WithCase(jsonflag.DashCase)},
+			env:    map[string]string{"APP_URL_HOST": "example.com", "APP_MAX_RETRIES": "3", "APP_max-retries": "ignored"},
+			want: func() *TestEnvBase {
+				b := &TestEnvBase{MaxRetries: 3}
+				b.URL.Host = "example.com"
+				return b
+			}(),
+		},
+		{
+			name:      "case-function-invalid-value",
+			prefix:    "APP",
+			opts:      []jsonflag.Option{jsonflag.WithCase(jsonflag.DashCase)},
+			env:       map[string]string{"APP_MAX_RETRIES": "invalid"},
+			wantError: `environment variable APP_MAX_RETRIES for flag max-retries: strconv.ParseInt: parsing "invalid": invalid syntax`,
+			want:      &TestEnvBase{},
+		},
+		{
+			name:      "invalid-value",
+			prefix:    "APP",
+			env:       map[string]string{"APP_URL_PORT": "invalid"},
+			wantError: `environment variable APP_URL_PORT for flag url.port: strconv.ParseInt: parsing "invalid": invalid syntax`,
+			want:      &TestEnvBase{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			given := &TestEnvBase{}
+			opts := append([]jsonflag.Option{jsonflag.WithLookupEnv(func(name string) (string, bool) {
+				v, ok := test.env[name]
+				return v, ok
+			})}, test.opts...)
+
+			err := jsonflag.ApplyEnv(jsonflag.Recursive(given), test.prefix, opts...)
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.want, given)
+		})
+	}
+}
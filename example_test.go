@@ -91,3 +91,106 @@ func ExampleRecursive_withPFlag() {
 	//   "Baz": "another baz value"
 	// }
 }
+
+func ExampleRegister() {
+	type Input struct {
+		FooBar string `json:"fooBar"`
+		Nested struct {
+			BazQux string `json:"bazQux"`
+		} `json:"nested"`
+	}
+
+	fs := flag.NewFlagSet("", flag.ExitOnError)
+	i := &Input{}
+	if _, err := jsonflag.Register(fs, i, jsonflag.WithName(jsonflag.Name), jsonflag.WithCase(jsonflag.DashCase)); err != nil {
+		panic(err)
+	}
+	if err := fs.Parse([]string{"--foo-bar=foo bar value", "--nested.baz-qux=baz qux value"}); err != nil {
+		panic(err)
+	}
+
+	b, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+
+	// Output:
+	// {
+	//   "fooBar": "foo bar value",
+	//   "nested": {
+	//     "bazQux": "baz qux value"
+	//   }
+	// }
+}
+
+func ExampleRegisterPFlags() {
+	type Input struct {
+		Foo     string `json:"foo" short:"f"`
+		Verbose bool   `json:"verbose" short:"v"`
+	}
+
+	fs := pflag.NewFlagSet("", pflag.ExitOnError)
+	i := &Input{}
+	if _, err := jsonflag.RegisterPFlags(fs, i); err != nil {
+		panic(err)
+	}
+	if err := fs.Parse([]string{"-f", "foo value", "-v"}); err != nil {
+		panic(err)
+	}
+
+	b, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+
+	// Output:
+	// {
+	//   "foo": "foo value",
+	//   "verbose": true
+	// }
+}
+
+func ExampleApplyEnv() {
+	type Input struct {
+		URL struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		} `json:"url"`
+	}
+
+	env := map[string]string{"APP_URL_HOST": "example.com", "APP_URL_PORT": "8080"}
+	i := &Input{}
+	err := jsonflag.ApplyEnv(jsonflag.Recursive(i), "APP", jsonflag.WithLookupEnv(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(i.URL.Host, i.URL.Port)
+
+	// Output:
+	// example.com 8080
+}
+
+func ExampleApplyDefaults() {
+	type Input struct {
+		URL struct {
+			Scheme string `json:"scheme" default:"https"`
+			Host   string `json:"host" default:"localhost"`
+			Port   int    `json:"port" default:"8080"`
+		} `json:"url"`
+	}
+
+	i := &Input{}
+	i.URL.Host = "example.com"
+	if err := jsonflag.ApplyDefaults(i); err != nil {
+		panic(err)
+	}
+	fmt.Println(i.URL.Scheme, i.URL.Host, i.URL.Port)
+
+	// Output:
+	// https example.com 8080
+}
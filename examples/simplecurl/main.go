@@ -28,8 +28,9 @@ func main() {
 	config.URL.Port = 8080
 
 	fs := flag.NewFlagSet("", flag.ExitOnError) // you can also add it directly to the root flag set
-	for _, val := range jsonflag.Recursive(&config) {
-		fs.Var(val, jsonflag.JSONName(val.Path()), jsonflag.Usage(val.Path()))
+	if _, err := jsonflag.Register(fs, &config); err != nil {
+		fmt.Fprintf(os.Stderr, "Flags registration error: %v\n", err)
+		os.Exit(1)
 	}
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Arguments parsing error: %v\n", err)
@@ -0,0 +1,214 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	errUnterminatedString  = errors.New("unterminated string")
+	errUnterminatedComment = errors.New("unterminated comment")
+)
+
+// JSONC converts JSON with comments into standard JSON. It accepts '//' and '/* */' comments and trailing commas in objects and lists and, if enabled, unquoted object keys (consisting of ASCII letters, digits, '_' and '$').
+type JSONC struct {
+	UnquotedKeys bool
+}
+
+// JSONCError is returned when decoding JSON with comments fails. It holds the position of the error in the original input.
+type JSONCError struct {
+	Line   int // line number, starting from 1
+	Column int // byte column in the line, starting from 1
+	Err    error
+}
+
+func (e *JSONCError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *JSONCError) Unwrap() error {
+	return e.Err
+}
+
+// Decode decodes JSON with comments into the provided value, as package "encoding/json" would do (but with numbers stored in interface values decoded as json.Number). Syntax and type errors are reported as JSONCError, holding the position in the original input. It can be used as decoder for flag values (see Value.SetJSONDecoder) and config files (see WithJSONDecoder).
+func (c JSONC) Decode(data []byte, v any) error {
+	std, insertions, err := c.standardize(data)
+	if err != nil {
+		return err
+	}
+	if err := unmarshalJSON(std, v); err != nil {
+		offset := int64(-1)
+		if syntaxErr := (*json.SyntaxError)(nil); errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset
+		} else if typeErr := (*json.UnmarshalTypeError)(nil); errors.As(err, &typeErr) {
+			offset = typeErr.Offset
+		}
+		if offset < 0 {
+			return err
+		}
+		offset -= int64(sort.SearchInts(insertions, int(offset))) // map offset in the standardized input back to the original one
+		return newJSONCError(data, int(offset)-1, err)
+	}
+	return nil
+}
+
+// Standardize converts JSON with comments into standard JSON. Comments and trailing commas are replaced with spaces, so that positions in the output match the original input, unless unquoted keys are quoted.
+func (c JSONC) Standardize(data []byte) ([]byte, error) {
+	std, _, err := c.standardize(data)
+	return std, err
+}
+
+// standardize converts JSON with comments into standard JSON. It returns also offsets in the output of all inserted bytes.
+func (c JSONC) standardize(data []byte) ([]byte, []int, error) {
+	out := make([]byte, 0, len(data))
+	insertions := []int(nil)
+	stack := []byte(nil)
+	expectKey := false
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b == '"':
+			end, err := skipString(data, i)
+			if err != nil {
+				return nil, nil, err
+			}
+			out = append(out, data[i:end]...)
+			i = end - 1
+			expectKey = false
+		case b == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			end, err := skipComment(data, i)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, x := range data[i:end] {
+				if x != '\n' && x != '\r' {
+					x = ' '
+				}
+				out = append(out, x)
+			}
+			i = end - 1
+		case b == ',':
+			if next := skipSpaceAndComments(data, i+1); next < len(data) && (data[next] == '}' || data[next] == ']') && followsValue(out) {
+				b = ' ' // trailing comma
+			}
+			out = append(out, b)
+			expectKey = len(stack) > 0 && stack[len(stack)-1] == '{'
+		case b == '{' || b == '[':
+			stack = append(stack, b)
+			out = append(out, b)
+			expectKey = b == '{'
+		case b == '}' || b == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			out = append(out, b)
+			expectKey = false
+		case c.UnquotedKeys && expectKey && isIdentByte(b, true):
+			end := i + 1
+			for end < len(data) && isIdentByte(data[end], false) {
+				end++
+			}
+			insertions = append(insertions, len(out))
+			out = append(out, '"')
+			out = append(out, data[i:end]...)
+			insertions = append(insertions, len(out))
+			out = append(out, '"')
+			i = end - 1
+			expectKey = false
+		default:
+			out = append(out, b)
+		}
+	}
+	return out, insertions, nil
+}
+
+func newJSONCError(data []byte, offset int, err error) *JSONCError {
+	offset = max(0, min(offset, len(data)))
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return &JSONCError{
+		Line:   bytes.Count(data[:offset], []byte{'\n'}) + 1,
+		Column: offset - lineStart + 1,
+		Err:    err,
+	}
+}
+
+// skipString returns offset just after the string starting at the provided offset.
+func skipString(data []byte, start int) (int, error) {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, newJSONCError(data, start, errUnterminatedString)
+}
+
+// skipComment returns offset just after the comment starting at the provided offset.
+func skipComment(data []byte, start int) (int, error) {
+	if data[start+1] == '/' {
+		if end := bytes.IndexByte(data[start:], '\n'); end >= 0 {
+			return start + end, nil
+		}
+		return len(data), nil
+	}
+	if end := bytes.Index(data[start+2:], []byte("*/")); end >= 0 {
+		return start + 2 + end + 2, nil
+	}
+	return 0, newJSONCError(data, start, errUnterminatedComment)
+}
+
+// skipSpaceAndComments returns offset of the first byte, starting from the provided offset, that is neither a white space nor a part of a comment.
+func skipSpaceAndComments(data []byte, start int) int {
+	i := start
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case data[i] == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			end, err := skipComment(data, i)
+			if err != nil {
+				return len(data)
+			}
+			i = end
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// followsValue reports whether the standardized output so far ends with a value (ignoring white spaces), so that a comma after it may be a trailing one.
+func followsValue(out []byte) bool {
+	trimmed := bytes.TrimRight(out, " \t\r\n")
+	if len(trimmed) == 0 {
+		return false
+	}
+	switch trimmed[len(trimmed)-1] {
+	case '{', '[', ',', ':':
+		return false
+	}
+	return true
+}
+
+func isIdentByte(b byte, first bool) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_' || b == '$' || !first && b >= '0' && b <= '9'
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+func TestJSONCStandardize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		unquotedKeys bool
+		given        string
+		want         string
+		wantError    string
+	}{
+		{
+			name:  "plain",
+			given: `{"a":[1,2],"b":"c"}`,
+			want:  `{"a":[1,2],"b":"c"}`,
+		},
+		{
+			name:  "line-comments",
+			given: "{\n// comment\n\"a\": 1 // comment\n}",
+			want:  "{\n          \n\"a\": 1           \n}",
+		},
+		{
+			name:  "block-comments",
+			given: "{/* a\nb */\"a\": 1}",
+			want:  "{    \n    \"a\": 1}",
+		},
+		{
+			name:  "trailing-commas",
+			given: `{"a":[1,2,],"b":{"c":3,/* comment */},}`,
+			want:  `{"a":[1,2 ],"b":{"c":3              } }`,
+		},
+		{
+			name:  "strings",
+			given: `{"a//b":"/* c */","d":",}","e\"//":1}`,
+			want:  `{"a//b":"/* c */","d":",}","e\"//":1}`,
+		},
+		{
+			name:  "unquoted-keys-disabled",
+			given: `{a:1}`,
+			want:  `{a:1}`,
+		},
+		{
+			name:  "comma-without-element",
+			given: `{"a":[,],"b":{,}}`,
+			want:  `{"a":[,],"b":{,}}`,
+		},
+		{
+			name:         "unquoted-keys",
+			unquotedKeys: true,
+			given:        `{a:1, $b_2: {c: [true, null]}, "d": e}`,
+			want:         `{"a":1, "$b_2": {"c": [true, null]}, "d": e}`,
+		},
+		{
+			name:      "unterminated-comment",
+			given:     "{\n  /* comment",
+			wantError: "line 2, column 3: unterminated comment",
+		},
+		{
+			name:      "unterminated-string",
+			given:     `{"a": "b}`,
+			wantError: "line 1, column 7: unterminated string",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := jsonflag.JSONC{UnquotedKeys: test.unquotedKeys}.Standardize([]byte(test.given))
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, string(got))
+		})
+	}
+}
+
+func TestJSONCDecode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		unquotedKeys bool
+		given        string
+		want         any
+		wantLine     int
+		wantColumn   int
+		wantError    string
+	}{
+		{
+			name:  "valid",
+			given: "{\n  // comment\n  \"host\": \"a\", /* comment */\n  \"port\": 1,\n}",
+			want:  &TestLoadBase{Host: "a", Port: 1},
+		},
+		{
+			name:         "valid-unquoted-keys",
+			unquotedKeys: true,
+			given:        "{host: \"a\", tags: [\"b\",],}",
+			want:         &TestLoadBase{Host: "a", Tags: []string{"b"}},
+		},
+		{
+			name:       "syntax-error",
+			given:      "{\n  // comment\n  \"host\": a\n}",
+			wantLine:   3,
+			wantColumn: 11,
+			wantError:  "line 3, column 11: invalid character 'a' looking for beginning of value",
+		},
+		{
+			name:         "syntax-error-after-unquoted-keys",
+			unquotedKeys: true,
+			given:        "{host: \"a\", port: x}",
+			wantLine:     1,
+			wantColumn:   19,
+			wantError:    "line 1, column 19: invalid character 'x' looking for beginning of value",
+		},
+		{
+			name:         "syntax-error-after-several-unquoted-keys",
+			unquotedKeys: true,
+			given:        "{a:1,b x}",
+			wantLine:     1,
+			wantColumn:   8,
+			wantError:    "line 1, column 8: invalid character 'x' after object key",
+		},
+		{
+			name:         "syntax-error-after-many-unquoted-keys",
+			unquotedKeys: true,
+			given:        "{host: \"a\", port: 1, tags: [], limits: x}",
+			wantLine:     1,
+			wantColumn:   40,
+			wantError:    "line 1, column 40: invalid character 'x' looking for beginning of value",
+		},
+		{
+			name:       "comma-without-list-element",
+			given:      "[,]",
+			wantLine:   1,
+			wantColumn: 2,
+			wantError:  "line 1, column 2: invalid character ',' looking for beginning of value",
+		},
+		{
+			name:       "comma-without-object-key",
+			given:      "{,}",
+			wantLine:   1,
+			wantColumn: 2,
+			wantError:  "line 1, column 2: invalid character ',' looking for beginning of object key string",
+		},
+		{
+			name:         "double-trailing-comma",
+			unquotedKeys: true,
+			given:        "{host: \"a\",,}",
+			wantLine:     1,
+			wantColumn:   12,
+			wantError:    "line 1, column 12: invalid character ',' looking for beginning of object key string",
+		},
+		{
+			name:       "type-error",
+			given:      "{\n  \"port\": \"a\"\n}",
+			wantLine:   2,
+			wantColumn: 13,
+			wantError:  "line 2, column 13: json: cannot unmarshal string into Go struct field TestLoadBase.port of type int",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := &TestLoadBase{}
+			err := jsonflag.JSONC{UnquotedKeys: test.unquotedKeys}.Decode([]byte(test.given), got)
+			if test.wantError != "" {
+				jsoncErr := (*jsonflag.JSONCError)(nil)
+				require.ErrorAs(t, err, &jsoncErr)
+				require.Equal(t, test.wantLine, jsoncErr.Line)
+				require.Equal(t, test.wantColumn, jsoncErr.Column)
+				require.EqualError(t, err, test.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestJSONCFlagValues(t *testing.T) {
+	t.Parallel()
+	given := &struct {
+		Struct TestStruct
+		Map    map[string]int
+		Slices [][]int
+	}{}
+	values := jsonflag.Recursive(given, jsonflag.MaxDepth(1))
+	for _, val := range values {
+		val.SetJSONDecoder(jsonflag.JSONC{UnquotedKeys: true}.Decode)
+	}
+	require.Len(t, values, 4)
+
+	require.NoError(t, values[1].Set(`{Value: "a", /* comment */}`))
+	require.NoError(t, values[2].Set(`{a: 1,}`))
+	require.NoError(t, values[2].Set(`b=2`))
+	require.NoError(t, values[3].Set(`[1, 2,]`))
+	require.NoError(t, values[3].Set(`[3] // comment`))
+	require.Equal(t, TestStruct{Value: "a"}, given.Struct)
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, given.Map)
+	require.Equal(t, [][]int{{1, 2}, {3}}, given.Slices)
+	require.EqualError(t, values[2].Set("{\n  a: x}"), "line 2, column 6: invalid character 'x' looking for beginning of value")
+}
@@ -17,17 +17,23 @@ package jsonflag
 
 import (
 	"reflect"
+	"slices"
 	"strings"
 	"unicode"
 )
 
-// Name creates a new flag name by joining all names of struct fields along the provided path.
+// Name creates a new flag name by joining all names of struct fields along the provided path. Untagged embedded structs are omitted, as their fields are promoted to the parent level.
 func Name(path []reflect.StructField) string {
 	b := strings.Builder{}
-	for i, p := range path {
-		if i != 0 {
+	dot := false
+	for _, p := range path {
+		if isEmbeddedStruct(p) {
+			continue
+		}
+		if dot {
 			b.WriteByte('.')
 		}
+		dot = true
 		b.WriteString(p.Name)
 	}
 	n := b.String()
@@ -37,11 +43,14 @@ func Name(path []reflect.StructField) string {
 	return n
 }
 
-// JSONName creates a new flag name by joining all JSON names (as package "encoding/json" would generate them) of struct fields along the provided path.
+// JSONName creates a new flag name by joining all JSON names (as package "encoding/json" would generate them) of struct fields along the provided path. Untagged embedded structs are omitted, as their fields are promoted to the parent level.
 func JSONName(path []reflect.StructField) string {
 	b := strings.Builder{}
 	dot := false
 	for _, p := range path {
+		if isEmbeddedStruct(p) {
+			continue
+		}
 		n := jsonFieldName(p)
 		if n == "" {
 			dot = false
@@ -66,16 +75,155 @@ func jsonFieldName(sf reflect.StructField) string {
 	if !ok {
 		return sf.Name
 	}
+	if tag == "-" {
+		return ""
+	}
 	tag, _, _ = strings.Cut(tag, ",")
 	if tag == "" {
 		return sf.Name
 	}
-	if tag == "-" {
-		return ""
+	return tag
+}
+
+// isJSONIgnored reports if the provided field is always ignored by package "encoding/json" due to `json:"-"` tag.
+//
+//nolint:gocritic // values of reflect.StructField are passed by value
+func isJSONIgnored(sf reflect.StructField) bool {
+	return sf.Tag.Get("json") == "-"
+}
+
+// hasJSONOption reports if JSON tag of the provided field contains the provided option, eg. "string" or "omitempty".
+//
+//nolint:gocritic // values of reflect.StructField are passed by value
+func hasJSONOption(sf reflect.StructField, option string) bool {
+	_, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
 	}
+	return false
+}
+
+//nolint:gocritic // values of reflect.StructField are passed by value
+func jsonTagName(sf reflect.StructField) string {
+	tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
 	return tag
 }
 
+// isEmbeddedStruct reports if the provided field is an embedded struct (or pointer to struct) without JSON name, which fields are promoted to the parent level by package "encoding/json".
+//
+//nolint:gocritic // values of reflect.StructField are passed by value
+func isEmbeddedStruct(sf reflect.StructField) bool {
+	return sf.Anonymous && jsonTagName(sf) == "" && !isJSONIgnored(sf) && elemIfPtrType(sf.Type).Kind() == reflect.Struct
+}
+
+type structField struct {
+	name   string
+	tagged bool
+	index  []int
+	path   []reflect.StructField
+}
+
+// structFields returns fields of the provided struct type that package "encoding/json" would use, including fields promoted from embedded structs, in the order of declaration. Fields hidden by the dominance rules of package "encoding/json" are omitted.
+func structFields(t reflect.Type) []structField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+		path  []reflect.StructField
+	}
+
+	fields := []structField(nil)
+	visited := map[reflect.Type]bool{}
+	next := []embedded{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		levelVisited := []reflect.Type(nil)
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			levelVisited = append(levelVisited, e.typ)
+			for i := range e.typ.NumField() {
+				sf := e.typ.Field(i)
+				if sf.Anonymous {
+					if !sf.IsExported() && (elemIfPtrType(sf.Type).Kind() != reflect.Struct || sf.Type.Kind() == reflect.Pointer) {
+						continue // embedded fields of unexported non-struct types and pointers to unexported struct types cannot be set
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				index := append(slices.Clone(e.index), i)
+				path := append(slices.Clone(e.path), sf)
+				if isEmbeddedStruct(sf) {
+					next = append(next, embedded{typ: elemIfPtrType(sf.Type), index: index, path: path})
+					continue
+				}
+				fields = append(fields, structField{name: jsonFieldName(sf), tagged: jsonTagName(sf) != "", index: index, path: path})
+			}
+		}
+		for _, t := range levelVisited { // the same type embedded multiple times at the same level is expanded each time, so that conflicting fields hide each other
+			visited[t] = true
+		}
+	}
+
+	byName := map[string][]int{}
+	for i, f := range fields {
+		if f.name != "" {
+			byName[f.name] = append(byName[f.name], i)
+		}
+	}
+	hidden := make([]bool, len(fields))
+	for _, indexes := range byName {
+		if dominant, ok := dominantField(fields, indexes); ok {
+			for _, i := range indexes {
+				hidden[i] = i != dominant
+			}
+			continue
+		}
+		for _, i := range indexes {
+			hidden[i] = true
+		}
+	}
+
+	visible := []structField(nil)
+	for i, f := range fields {
+		if !hidden[i] {
+			visible = append(visible, f)
+		}
+	}
+	slices.SortFunc(visible, func(a, b structField) int { return slices.Compare(a.index, b.index) })
+	return visible
+}
+
+// dominantField returns the field that dominates all the others with the same name, following the rules of package "encoding/json". The shallowest field wins, unless there are multiple such fields - then the only tagged one wins. If no field dominates, false is returned.
+func dominantField(fields []structField, indexes []int) (int, bool) {
+	depth := len(fields[indexes[0]].index)
+	for _, i := range indexes {
+		depth = min(depth, len(fields[i].index))
+	}
+	dominant, count, tagged := -1, 0, 0
+	for _, i := range indexes {
+		if len(fields[i].index) != depth {
+			continue
+		}
+		count++
+		if fields[i].tagged {
+			tagged++
+			dominant = i
+		} else if tagged == 0 {
+			dominant = i
+		}
+	}
+	if count == 1 || tagged == 1 {
+		return dominant, true
+	}
+	return -1, false
+}
+
 // Usage attempts to retrieve from the last element of path a tag value under key 'usage', 'description' or 'desc'.
 func Usage(path []reflect.StructField) string {
 	if len(path) == 0 {
@@ -93,6 +241,20 @@ func Usage(path []reflect.StructField) string {
 	return ""
 }
 
+func lastTag(path []reflect.StructField, key string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	return path[len(path)-1].Tag.Get(key)
+}
+
+func lastTagLookup(path []reflect.StructField, key string) (string, bool) {
+	if len(path) == 0 {
+		return "", false
+	}
+	return path[len(path)-1].Tag.Lookup(key)
+}
+
 // JsonCamelCase converts Go camel case flag name into JSON (javascript) camel case flag name, eg. "Foo.FooBar.FooBarBaz" to "foo.fooBar.fooBarBaz".
 func JsonCamelCase(s string) string {
 	if s == "" {
@@ -150,3 +312,8 @@ func DashCase(s string) string {
 	}
 	return string(result)
 }
+
+// EnvCase converts Go camel case flag name into environment variable name, eg. "Foo.FooBar.FooBarBaz" to "FOO_FOO_BAR_FOO_BAR_BAZ".
+func EnvCase(s string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(SnakeCase(s)))
+}
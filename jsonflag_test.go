@@ -44,6 +44,21 @@ func TestName(t *testing.T) {
 			given: []reflect.StructField{{Name: "Foo"}, {Name: "Bar"}},
 			want:  "Foo.Bar",
 		},
+		{
+			name:  "embedded-struct",
+			given: []reflect.StructField{{Name: "Foo"}, {Name: "Embedded", Type: Type[TestStruct](), Anonymous: true}, {Name: "Bar"}},
+			want:  "Foo.Bar",
+		},
+		{
+			name:  "embedded-struct-tagged",
+			given: []reflect.StructField{{Name: "Foo"}, {Name: "Embedded", Type: Type[TestStruct](), Tag: `json:"embedded"`, Anonymous: true}, {Name: "Bar"}},
+			want:  "Foo.Embedded.Bar",
+		},
+		{
+			name:  "embedded-non-struct",
+			given: []reflect.StructField{{Name: "Foo"}, {Name: "Embedded", Type: Type[int](), Anonymous: true}},
+			want:  "Foo.Embedded",
+		},
 	}
 
 	for _, test := range tests {
@@ -82,6 +97,11 @@ func TestJSONName(t *testing.T) {
 			given: []reflect.StructField{{Name: "Foo", Tag: `json:"-"`}},
 			want:  "input",
 		},
+		{
+			name:  "single-field/tag-dash-name",
+			given: []reflect.StructField{{Name: "Foo", Tag: `json:"-,"`}},
+			want:  "-",
+		},
 		{
 			name:  "single-field/tag-empty-name",
 			given: []reflect.StructField{{Name: "Foo", Tag: `json:""`}},
@@ -117,6 +137,26 @@ func TestJSONName(t *testing.T) {
 			given: []reflect.StructField{{Name: "Foo", Tag: `json:"foo"`}, {Name: "Bar"}},
 			want:  "foo.Bar",
 		},
+		{
+			name:  "embedded-struct",
+			given: []reflect.StructField{{Name: "Foo", Tag: `json:"foo"`}, {Name: "Embedded", Type: Type[TestStruct](), Anonymous: true}, {Name: "Bar", Tag: `json:"bar"`}},
+			want:  "foo.bar",
+		},
+		{
+			name:  "embedded-ptr-to-struct",
+			given: []reflect.StructField{{Name: "Foo", Tag: `json:"foo"`}, {Name: "Embedded", Type: Type[*TestStruct](), Tag: `json:",omitempty"`, Anonymous: true}, {Name: "Bar", Tag: `json:"bar"`}},
+			want:  "foo.bar",
+		},
+		{
+			name:  "embedded-struct-tagged",
+			given: []reflect.StructField{{Name: "Foo", Tag: `json:"foo"`}, {Name: "Embedded", Type: Type[TestStruct](), Tag: `json:"embedded"`, Anonymous: true}, {Name: "Bar", Tag: `json:"bar"`}},
+			want:  "foo.embedded.bar",
+		},
+		{
+			name:  "embedded-non-struct",
+			given: []reflect.StructField{{Name: "Foo", Tag: `json:"foo"`}, {Name: "Embedded", Type: Type[int](), Anonymous: true}},
+			want:  "foo.Embedded",
+		},
 	}
 
 	for _, test := range tests {
@@ -259,3 +299,27 @@ func TestDashCase(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvCase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		given string
+		want  string
+	}{
+		{name: "empty", given: "", want: ""},
+		{name: "singlePart", given: "Foo", want: "FOO"},
+		{name: "doublePart", given: "FooBar", want: "FOO_BAR"},
+		{name: "singlePart/singlePart", given: "Foo.Bar", want: "FOO_BAR"},
+		{name: "doublePart/doublePart", given: "FooBar.FooBaz", want: "FOO_BAR_FOO_BAZ"},
+		{name: "jsonCamelCase/dashCase", given: "fooBar.foo-baz", want: "FOO_BAR_FOO_BAZ"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := jsonflag.EnvCase(test.given)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
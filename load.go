@@ -0,0 +1,286 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownKey is returned when a config file contains a key that does not match any field.
+var ErrUnknownKey = errors.New("jsonflag: unknown key")
+
+// WithConfigFlag sets the name of the flag holding path to JSON config file, used by Load. By default "config" is used. Empty name disables config file loading.
+func WithConfigFlag(name string) Option {
+	return func(o *options) {
+		o.configFlag = name
+	}
+}
+
+// WithEnv enables setting values from environment variables with the provided prefix in Load (see ApplyEnv).
+func WithEnv(prefix string) Option {
+	return func(o *options) {
+		o.useEnv = true
+		o.envPrefix = prefix
+	}
+}
+
+// Load registers in the provided flag set flag values for the provided value (see Register) and populates the value from the following sources, in order of increasing precedence: initial content of the value (defaults), JSON config file pointed by the config flag (see WithConfigFlag and WithJSONDecoder), environment variables (see WithEnv) and command line arguments. Keys in the config file not matching any field are reported as an error, as well as required values that have not been provided by any source (see CheckRequired). It returns the registered flag values.
+//
+// Command line arguments are parsed before loading the config file (as its path may come from a flag), but their values are set only after all other sources have been applied. Unlike Register, Load defines also flags for map keys and slice indexes that are not present in the value, but are referenced by the arguments, eg. "--servers.us.port" (see Value.Lookup). Such flags are returned after the registered ones.
+func Load(fs *flag.FlagSet, base any, args []string, opts ...Option) ([]*Value, error) {
+	o := newOptions(opts)
+	values := o.recursive(base)
+	if o.configFlag != "" && (fs.Lookup(o.configFlag) != nil || slices.ContainsFunc(values, func(val *Value) bool { return o.name(val) == o.configFlag })) {
+		return nil, fmt.Errorf("%w: %s", ErrFlagRedefined, o.configFlag)
+	}
+	sets := []deferredSet(nil)
+	wrap := func(val *Value, name string) flag.Value {
+		return &deferredValue{val: val, name: name, sets: &sets}
+	}
+	if err := register(fs, o, values, wrap); err != nil {
+		return nil, err
+	}
+	if elems := defineElems(fs, o, values, args, wrap); len(elems) > 0 {
+		values = append(values, elems...)
+		linkChildren(values)
+	}
+	configPath := (*string)(nil)
+	if o.configFlag != "" {
+		configPath = fs.String(o.configFlag, "", "path to JSON config file")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if configPath != nil && *configPath != "" {
+		data, err := loadFile(*configPath, base, o.decodeJSON)
+		if err != nil {
+			return nil, err
+		}
+		recordFile(values, *configPath, data)
+	}
+	if o.useEnv {
+		if err := ApplyEnv(values, o.envPrefix, opts...); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range sets {
+		if err := s.val.setFrom(Source{Kind: SourceFlag, Name: "-" + s.name, Input: s.to}, s.to); err != nil {
+			return nil, fmt.Errorf("invalid value %q for flag -%s: %w", s.val.redact(s.to), s.name, s.val.redactError(err))
+		}
+	}
+	if err := CheckRequired(values, opts...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// defineElems defines in the provided flag set flags for map elements and slice elements referenced by the provided arguments, but not defined yet (see Value.Lookup). Path within the element is given by flag names of struct fields, as for registered flags. It returns flag values of the defined flags.
+func defineElems(fs *flag.FlagSet, o *options, values []*Value, args []string, wrap func(val *Value, name string) flag.Value) []*Value {
+	defined := []*Value(nil)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" { // arguments parsing stops at the first non-flag argument, as in package "flag"
+			break
+		}
+		name, _, hasValue := strings.Cut(strings.TrimPrefix(arg[1:], "-"), "=")
+		f := fs.Lookup(name)
+		if f == nil {
+			if val := lookupElem(o, values, name); val != nil {
+				val.recordDefault()
+				fs.Var(wrap(val, name), name, o.usage(val))
+				defined = append(defined, val)
+				f = fs.Lookup(name)
+			}
+		}
+		if f == nil || hasValue {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); !ok || !b.IsBoolFlag() {
+			i++ // skip the flag value
+		}
+	}
+	return defined
+}
+
+// lookupElem returns flag value for the provided flag name, being a name of a map or a slice flag value followed by path within it (see Value.Lookup). The longest matching flag name is used. It returns nil if there is no such flag value.
+func lookupElem(o *options, values []*Value, name string) *Value {
+	container, prefix := (*Value)(nil), ""
+	for _, val := range values {
+		n := o.name(val)
+		switch elemIfPtrType(val.typ()).Kind() { //nolint:exhaustive // cases for only container types
+		case reflect.Map, reflect.Slice:
+		default:
+			continue
+		}
+		if strings.HasPrefix(name, n+".") && len(n) > len(prefix) {
+			container, prefix = val, n
+		}
+	}
+	if container == nil {
+		return nil
+	}
+	val := container
+	for _, segment := range strings.Split(strings.TrimPrefix(name, prefix+"."), ".") {
+		if val = lookupSegment(o, val, segment); val == nil {
+			return nil
+		}
+	}
+	if o.decodeFn != nil {
+		val.SetJSONDecoder(o.decodeFn)
+	}
+	o.setResolvers([]*Value{val})
+	return val
+}
+
+// lookupSegment returns flag value for the provided segment of flag name, relative to the provided flag value. Struct fields are matched by the last segment of their flag names (see WithName and WithCase), while map keys and slice indexes are taken as is (see Value.Lookup). It returns nil if there is no such flag value.
+func lookupSegment(o *options, val *Value, segment string) *Value {
+	t := elemIfPtrType(val.typ())
+	if t.Kind() != reflect.Struct || isTextUnmarshaler(t) {
+		sub, err := val.lookup(segment)
+		if err != nil {
+			return nil
+		}
+		return sub
+	}
+	for _, f := range structFields(t) {
+		sub, err := val.lookup(f.name)
+		if err != nil {
+			continue
+		}
+		if n := o.name(sub); n[strings.LastIndexByte(n, '.')+1:] == segment {
+			return sub
+		}
+	}
+	return nil
+}
+
+// deferredSet is a flag value set postponed until all other sources are applied.
+type deferredSet struct {
+	val  *Value
+	name string
+	to   string
+}
+
+// deferredValue is a flag value that records sets instead of applying them.
+type deferredValue struct {
+	val  *Value
+	name string
+	sets *[]deferredSet
+}
+
+func (d *deferredValue) String() string {
+	return d.val.String()
+}
+
+func (d *deferredValue) Set(to string) error {
+	*d.sets = append(*d.sets, deferredSet{val: d.val, name: d.name, to: to})
+	return nil
+}
+
+func (d *deferredValue) IsBoolFlag() bool {
+	return d.val.IsBoolFlag()
+}
+
+// loadFile decodes config file into the provided value. It returns the file content.
+func loadFile(path string, base any, decode func([]byte, any) error) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeConfig(data, base, decode); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// decodeConfig decodes config, with the provided function, into the provided value, reporting keys not matching any field as an error.
+func decodeConfig(data []byte, base any, decode func([]byte, any) error) error {
+	generic := any(nil)
+	if err := decode(data, &generic); err != nil {
+		return err
+	}
+	if err := checkKeys(reflect.TypeOf(base), generic, nil); err != nil {
+		return err
+	}
+	return decode(data, base)
+}
+
+// checkKeys reports the first key in the provided generic JSON data not matching any struct field of the provided type.
+func checkKeys(t reflect.Type, data any, path []string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if isTextUnmarshaler(t) || isJSONUnmarshaler(t) {
+		return nil
+	}
+	switch data := data.(type) {
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(data)) {
+			elemType := reflect.Type(nil)
+			switch t.Kind() { //nolint:exhaustive // cases for only types holding JSON objects
+			case reflect.Struct:
+				f, ok := findStructField(structFields(t), k)
+				if !ok {
+					return fmt.Errorf("%w: %s", ErrUnknownKey, strings.Join(append(path, k), "."))
+				}
+				elemType = f.path[len(f.path)-1].Type
+			case reflect.Map:
+				elemType = t.Elem()
+			default:
+				return nil
+			}
+			if err := checkKeys(elemType, data[k], append(path, k)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		for i, x := range data {
+			if err := checkKeys(t.Elem(), x, append(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findStructField finds field by JSON name, preferring an exact match, but otherwise accepting a case-insensitive match, as package "encoding/json" does.
+func findStructField(fields []structField, name string) (structField, bool) {
+	if name == "" { // ignored fields have no name
+		return structField{}, false
+	}
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
@@ -0,0 +1,433 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestLoadBase struct {
+	Host    string            `json:"host"`
+	Port    int               `json:"port"`
+	Tags    []string          `json:"tags"`
+	Limits  map[string]int    `json:"limits"`
+	Servers []TestStruct      `json:"servers"`
+	Ignored string            `json:"-"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		file      string
+		args      []string
+		env       map[string]string
+		opts      []jsonflag.Option
+		wantIs    error
+		wantError string
+		want      *TestLoadBase
+	}{
+		{
+			name: "defaults",
+			want: &TestLoadBase{Host: "localhost", Port: 80, Tags: []string{"default"}},
+		},
+		{
+			name: "file",
+			file: `{"port":8080,"tags":["a"],"limits":{"cpu":2}}`,
+			args: []string{"--config=CONFIG"},
+			want: &TestLoadBase{Host: "localhost", Port: 8080, Tags: []string{"a"}, Limits: map[string]int{"cpu": 2}},
+		},
+		{
+			name: "file-env-flags",
+			file: `{"host":"file","port":8080,"tags":["a"]}`,
+			args: []string{"--port=9090", "--config", "CONFIG", "--tags=b"},
+			env:  map[string]string{"APP_HOST": "env", "APP_PORT": "1"},
+			opts: []jsonflag.Option{jsonflag.WithEnv("APP")},
+			want: &TestLoadBase{Host: "env", Port: 9090, Tags: []string{"b"}},
+		},
+		{
+			name: "env-with-case-function",
+			args: []string{"--port=9090"},
+			env:  map[string]string{"APP_HOST": "env", "APP_PORT": "1"},
+			opts: []jsonflag.Option{jsonflag.WithCase(jsonflag.DashCase), jsonflag.WithEnv("APP")},
+			want: &TestLoadBase{Host: "env", Port: 9090, Tags: []string{"default"}},
+		},
+		{
+			name: "custom-config-flag",
+			file: `{"host":"file"}`,
+			args: []string{"--settings=CONFIG"},
+			opts: []jsonflag.Option{jsonflag.WithConfigFlag("settings")},
+			want: &TestLoadBase{Host: "file", Port: 80, Tags: []string{"default"}},
+		},
+		{
+			name: "case-insensitive-key",
+			file: `{"HOST":"file"}`,
+			args: []string{"--config=CONFIG"},
+			want: &TestLoadBase{Host: "file", Port: 80, Tags: []string{"default"}},
+		},
+		{
+			name: "jsonc-file",
+			file: "{\n  // comment\n  port: 8080,\n}",
+			args: []string{"--config=CONFIG", "--limits={cpu: 2}"},
+			opts: []jsonflag.Option{jsonflag.WithJSONDecoder(jsonflag.JSONC{UnquotedKeys: true}.Decode)},
+			want: &TestLoadBase{Host: "localhost", Port: 8080, Tags: []string{"default"}, Limits: map[string]int{"cpu": 2}},
+		},
+		{
+			name:      "jsonc-file-error",
+			file:      "{\n  port: x,\n}",
+			args:      []string{"--config=CONFIG"},
+			opts:      []jsonflag.Option{jsonflag.WithJSONDecoder(jsonflag.JSONC{UnquotedKeys: true}.Decode)},
+			wantError: "config file CONFIG: line 2, column 9: invalid character 'x' looking for beginning of value",
+		},
+		{
+			name: "yaml-file",
+			file: "port: 8080\nlimits:\n  cpu: 2\n",
+			args: []string{"--config=CONFIG"},
+			opts: []jsonflag.Option{jsonflag.WithJSONDecoder(jsonflag.DecodeYAML)},
+			want: &TestLoadBase{Host: "localhost", Port: 8080, Tags: []string{"default"}, Limits: map[string]int{"cpu": 2}},
+		},
+		{
+			name:      "yaml-file-unknown-key",
+			file:      "prot: 8080\n",
+			args:      []string{"--config=CONFIG"},
+			opts:      []jsonflag.Option{jsonflag.WithJSONDecoder(jsonflag.DecodeYAML)},
+			wantIs:    jsonflag.ErrUnknownKey,
+			wantError: "config file CONFIG: jsonflag: unknown key: prot",
+		},
+		{
+			name:      "unknown-key",
+			file:      `{"host":"file","servers":[{"Value":"a"},{"Valeu":"b"}]}`,
+			args:      []string{"--config=CONFIG"},
+			wantIs:    jsonflag.ErrUnknownKey,
+			wantError: "config file CONFIG: jsonflag: unknown key: servers.1.Valeu",
+		},
+		{
+			name:      "ignored-key",
+			file:      `{"-":"x"}`,
+			args:      []string{"--config=CONFIG"},
+			wantIs:    jsonflag.ErrUnknownKey,
+			wantError: "config file CONFIG: jsonflag: unknown key: -",
+		},
+		{
+			name:      "config-flag-collision",
+			opts:      []jsonflag.Option{jsonflag.WithConfigFlag("host")},
+			wantIs:    jsonflag.ErrFlagRedefined,
+			wantError: "jsonflag: flag redefined: host",
+		},
+		{
+			name:      "invalid-flag-value",
+			args:      []string{"--port=invalid"},
+			wantError: `invalid value "invalid" for flag -port: strconv.ParseInt: parsing "invalid": invalid syntax`,
+		},
+		{
+			name:      "invalid-env-value",
+			env:       map[string]string{"PORT": "invalid"},
+			opts:      []jsonflag.Option{jsonflag.WithEnv("")},
+			wantError: `environment variable PORT for flag port: strconv.ParseInt: parsing "invalid": invalid syntax`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config.json")
+			if test.file != "" {
+				require.NoError(t, os.WriteFile(path, []byte(test.file), 0o600))
+			}
+			args := []string{}
+			for _, a := range test.args {
+				args = append(args, strings.ReplaceAll(a, "CONFIG", path))
+			}
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			opts := append([]jsonflag.Option{jsonflag.WithLookupEnv(func(name string) (string, bool) {
+				v, ok := test.env[name]
+				return v, ok
+			})}, test.opts...)
+			given := &TestLoadBase{Host: "localhost", Port: 80, Tags: []string{"default"}}
+
+			values, err := jsonflag.Load(fs, given, args, opts...)
+			if test.wantError != "" {
+				if test.wantIs != nil {
+					require.ErrorIs(t, err, test.wantIs)
+				}
+				require.Error(t, err)
+				require.Equal(t, test.wantError, strings.ReplaceAll(err.Error(), path, "CONFIG"))
+				require.Nil(t, values)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, values)
+			require.Equal(t, test.want, given)
+		})
+	}
+}
+
+func TestLoadConfigField(t *testing.T) {
+	t.Parallel()
+	given := &struct {
+		Config string `json:"config"`
+	}{}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	values, err := jsonflag.Load(fs, given, []string{"--config=a"})
+	require.ErrorIs(t, err, jsonflag.ErrFlagRedefined)
+	require.EqualError(t, err, "jsonflag: flag redefined: config")
+	require.Nil(t, values)
+
+	fs = flag.NewFlagSet("", flag.ContinueOnError)
+	_, err = jsonflag.Load(fs, given, []string{"--config=a"}, jsonflag.WithConfigFlag(""))
+	require.NoError(t, err)
+	require.Equal(t, "a", given.Config)
+}
+
+func TestLoadSecretError(t *testing.T) {
+	t.Parallel()
+	type Base struct {
+		Pin  int `json:"pin" secret:"true"`
+		Auth struct {
+			Pin int `json:"pin" secret:"true"`
+		} `json:"auth"`
+	}
+	tests := []struct {
+		name      string
+		args      []string
+		env       map[string]string
+		wantError string
+	}{
+		{
+			name:      "flag",
+			args:      []string{"-pin=hunter2"},
+			wantError: `invalid value "***" for flag -pin: jsonflag: invalid secret value, want int`,
+		},
+		{
+			name:      "flag-secret-field",
+			args:      []string{`-auth={"pin":"hunter2"}`},
+			wantError: `invalid value "{\"pin\":\"***\"}" for flag -auth: jsonflag: invalid secret value, want JSON object`,
+		},
+		{
+			name:      "env",
+			env:       map[string]string{"PIN": "hunter2"},
+			wantError: `environment variable PIN for flag pin: jsonflag: invalid secret value, want int`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			_, err := jsonflag.Load(fs, &Base{}, test.args, jsonflag.WithEnv(""), jsonflag.WithLookupEnv(func(name string) (string, bool) {
+				v, ok := test.env[name]
+				return v, ok
+			}))
+			require.ErrorIs(t, err, jsonflag.ErrInvalidSecret)
+			require.EqualError(t, err, test.wantError)
+			require.NotContains(t, err.Error(), "hunter2")
+		})
+	}
+}
+
+func TestLoadArray(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want [2]int
+	}{
+		{
+			name: "env",
+			env:  map[string]string{"APP_COORDS": "[1,2]"},
+			want: [2]int{1, 2},
+		},
+		{
+			name: "env-and-flag",
+			env:  map[string]string{"APP_COORDS": "[1,2]"},
+			args: []string{"--coords=5"},
+			want: [2]int{5, 2},
+		},
+		{
+			name: "env-and-flags",
+			env:  map[string]string{"APP_COORDS": "[1,2]"},
+			args: []string{"--coords=5", "--coords=6"},
+			want: [2]int{5, 6},
+		},
+		{
+			name: "env-element-and-flag",
+			env:  map[string]string{"APP_COORDS": "1"},
+			args: []string{"--coords=5"},
+			want: [2]int{5, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			given := &struct {
+				Coords [2]int `json:"coords"`
+			}{}
+			_, err := jsonflag.Load(fs, given, test.args, jsonflag.WithEnv("APP"), jsonflag.WithLookupEnv(func(name string) (string, bool) {
+				v, ok := test.env[name]
+				return v, ok
+			}))
+			require.NoError(t, err)
+			require.Equal(t, test.want, given.Coords)
+		})
+	}
+}
+
+type TestLoadElemsServer struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	MaxConns int    `json:"maxConns"`
+}
+
+type TestLoadElemsBase struct {
+	Servers  []TestLoadElemsServer          `json:"servers"`
+	Regions  map[string]TestLoadElemsServer `json:"regions"`
+	Features map[string]bool                `json:"features"`
+	Verbose  bool                           `json:"verbose"`
+}
+
+func TestLoadElems(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		file      string
+		args      []string
+		opts      []jsonflag.Option
+		wantError string
+		want      *TestLoadElemsBase
+	}{
+		{
+			name: "slice-expanded",
+			args: []string{"--servers.0.host=x", "--servers.1.host=y"},
+			opts: []jsonflag.Option{jsonflag.WithFilters(jsonflag.ExpandSlices)},
+			want: &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "x", Port: 80}, {Host: "y"}},
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}},
+			},
+		},
+		{
+			name: "slice-not-expanded",
+			args: []string{"--servers.2.host", "y"},
+			want: &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "default", Port: 80}, {}, {Host: "y"}},
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}},
+			},
+		},
+		{
+			name: "slice-from-file",
+			file: `{"servers":[{"host":"a"},{"host":"b"}]}`,
+			args: []string{"--config=CONFIG", "--servers.1.port=2"},
+			want: &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "a", Port: 80}, {Host: "b", Port: 2}}, // elements are decoded in place, as package "encoding/json" does
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}},
+			},
+		},
+		{
+			name: "map",
+			args: []string{"--regions.us.port=9000", "--regions.eu.host=eu2"},
+			opts: []jsonflag.Option{jsonflag.WithFilters(jsonflag.ExpandMaps)},
+			want: &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "default", Port: 80}},
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu2", Port: 80}, "us": {Port: 9000}},
+			},
+		},
+		{
+			name: "map-bool",
+			args: []string{"--features.beta", "--verbose"},
+			want: &TestLoadElemsBase{
+				Servers:  []TestLoadElemsServer{{Host: "default", Port: 80}},
+				Regions:  map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}},
+				Features: map[string]bool{"beta": true},
+				Verbose:  true,
+			},
+		},
+		{
+			name: "case-function",
+			args: []string{"--servers.1.max-conns=3", "--regions.us.max-conns=4"},
+			opts: []jsonflag.Option{jsonflag.WithCase(jsonflag.DashCase), jsonflag.WithFilters(jsonflag.ExpandSlices)},
+			want: &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "default", Port: 80}, {MaxConns: 3}},
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}, "us": {MaxConns: 4}},
+			},
+		},
+		{
+			name:      "case-function-json-name",
+			args:      []string{"--servers.1.maxConns=3"},
+			opts:      []jsonflag.Option{jsonflag.WithCase(jsonflag.DashCase)},
+			wantError: "flag provided but not defined: -servers.1.maxConns",
+		},
+		{
+			name:      "invalid-index",
+			args:      []string{"--servers.x.host=y"},
+			wantError: "flag provided but not defined: -servers.x.host",
+		},
+		{
+			name:      "unknown-field",
+			args:      []string{"--regions.us.prot=1"},
+			wantError: "flag provided but not defined: -regions.us.prot",
+		},
+		{
+			name: "after-non-flag-argument",
+			args: []string{"arg", "--servers.1.host=y"},
+			want: &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "default", Port: 80}},
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			args := slices.Clone(test.args)
+			if test.file != "" {
+				path := filepath.Join(t.TempDir(), "config.json")
+				require.NoError(t, os.WriteFile(path, []byte(test.file), 0o600))
+				for i := range args {
+					args[i] = strings.ReplaceAll(args[i], "CONFIG", path)
+				}
+			}
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			given := &TestLoadElemsBase{
+				Servers: []TestLoadElemsServer{{Host: "default", Port: 80}},
+				Regions: map[string]TestLoadElemsServer{"eu": {Host: "eu", Port: 80}},
+			}
+
+			_, err := jsonflag.Load(fs, given, args, test.opts...)
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, given)
+		})
+	}
+}
@@ -14,7 +14,34 @@
 
 package jsonflag
 
-import "strconv"
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	errJSONTrailingData   = errors.New("invalid data after top-level JSON value")
+	errUnsupportedMapKey  = errors.New("unsupported map key type")
+	errUnsupportedMapElem = errors.New("unsupported map element type")
+	errListQuote          = errors.New("unterminated quoted list value")
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()            //nolint:gochecknoglobals // type used for comparisons
+	timeType            = reflect.TypeFor[time.Time]()                //nolint:gochecknoglobals // type used for comparisons
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()         //nolint:gochecknoglobals // type used for comparisons
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()   //nolint:gochecknoglobals // type used for comparisons
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]() //nolint:gochecknoglobals // type used for comparisons
+)
 
 func parseInt(s string) (int, error) {
 	v, err := strconv.ParseInt(s, 0, 0)
@@ -81,3 +108,193 @@ func parseComplex64(s string) (complex64, error) {
 func parseComplex128(s string) (complex128, error) {
 	return strconv.ParseComplex(s, 128)
 }
+
+func parseDuration(s string) (time.Duration, error) {
+	return time.ParseDuration(s)
+}
+
+func parseTime(layout, s string) (time.Time, error) {
+	return time.Parse(layout, s)
+}
+
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func isJSONUnmarshaler(t reflect.Type) bool {
+	return t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+}
+
+// parseText returns a pointer to a new value of the provided type, which implements encoding.TextUnmarshaler (directly or through pointer), parsed from the provided string.
+func parseText(t reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(t)
+	if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil { //nolint:forcetypeassert // type should be checked by isTextUnmarshaler
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// textString formats the provided value using encoding.TextMarshaler, if implemented (directly or through pointer).
+func textString(v reflect.Value) string {
+	v = addressable(v).Addr()
+	if !v.Type().Implements(textMarshalerType) {
+		return fmt.Sprint(v.Elem().Interface())
+	}
+	b, err := v.Interface().(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert // type checked above
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// unmarshalJSON works like json.Unmarshal, but numbers stored in interface values are decoded as json.Number to preserve their precision.
+func unmarshalJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errJSONTrailingData
+	}
+	return nil
+}
+
+// parseInterface returns an interface value decoded from the provided JSON string. If the string is not a valid JSON, it is returned as is.
+func parseInterface(s string) reflect.Value {
+	v := any(nil)
+	if err := unmarshalJSON([]byte(s), &v); err != nil {
+		v = s
+	}
+	return reflect.ValueOf(&v).Elem()
+}
+
+// parseMapKey parses map key of the provided type, as package "encoding/json" would do for JSON object keys.
+func parseMapKey(t reflect.Type, s string) (reflect.Value, error) {
+	if isTextUnmarshaler(t) {
+		v, err := parseText(t, s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return v.Elem(), nil
+	}
+	v := reflect.New(t).Elem()
+	switch t.Kind() { //nolint:exhaustive // cases for only supported types
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(n)
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: %s", errUnsupportedMapKey, t)
+	}
+	return v, nil
+}
+
+// formatMapKey formats map key, as package "encoding/json" would do for JSON object keys.
+func formatMapKey(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.String {
+		return k.String(), true
+	}
+	if k.Type().Implements(textMarshalerType) {
+		b, err := k.Interface().(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert // type checked above
+		return string(b), err == nil
+	}
+	switch k.Kind() { //nolint:exhaustive // cases for only supported types
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), true
+	}
+	return "", false
+}
+
+// splitList splits separated list of values. Values starting with '"' are quoted as in package "encoding/csv" (with '""' standing for a single '"'), while other values are taken as is, including any '"' within them. Separators within JSON objects and lists of values (eg. `eu={"host":"a","port":1}`) do not split them.
+func splitList(s string, sep rune) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	elems := []string(nil)
+	for {
+		elem, rest, err := cutListElem(s, sep)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+		if rest == nil {
+			return elems, nil
+		}
+		s = *rest
+	}
+}
+
+// cutListElem cuts the first value of separated list, returning the value and the rest of the list after the separator (nil if there is no separator).
+func cutListElem(s string, sep rune) (string, *string, error) {
+	b := strings.Builder{}
+	if strings.HasPrefix(s, `"`) {
+		for i := 1; ; i++ {
+			if i >= len(s) {
+				return "", nil, fmt.Errorf("%w: %s", errListQuote, s)
+			}
+			if s[i] != '"' {
+				b.WriteByte(s[i])
+				continue
+			}
+			if strings.HasPrefix(s[i+1:], `"`) {
+				b.WriteByte('"')
+				i++
+				continue
+			}
+			s = s[i+1:]
+			break
+		}
+	}
+	depth, inString, escaped := 0, false, false
+	for i, r := range s {
+		switch {
+		case inString:
+			inString = escaped || r != '"'
+			escaped = !escaped && r == '\\'
+		case depth > 0 && r == '"':
+			inString = true
+		case r == '{' || r == '[':
+			depth++
+		case depth > 0 && (r == '}' || r == ']'):
+			depth--
+		case depth == 0 && r == sep:
+			rest := s[i+utf8.RuneLen(r):]
+			b.WriteString(s[:i])
+			return b.String(), &rest, nil
+		}
+	}
+	b.WriteString(s)
+	return b.String(), nil, nil
+}
+
+// splitJSONList splits JSON list, decoded with the provided function, into its elements. Elements being JSON strings are unquoted, unless raw elements are requested.
+func splitJSONList(s string, raw bool, unmarshal func(string, any) error) ([]string, error) {
+	list := []json.RawMessage(nil)
+	if err := unmarshal(s, &list); err != nil {
+		return nil, err
+	}
+	elems := make([]string, len(list))
+	for i, x := range list {
+		elems[i] = string(x)
+		if raw || len(x) == 0 || x[0] != '"' {
+			continue
+		}
+		if err := json.Unmarshal(x, &elems[i]); err != nil {
+			return nil, err
+		}
+	}
+	return elems, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// ErrInvalidShorthand is returned when a flag shorthand (from 'short' tag) is not a single ASCII character.
+var ErrInvalidShorthand = errors.New("jsonflag: invalid flag shorthand")
+
+// RegisterPFlags registers in the provided pflag flag set flag values for the provided value and all values within, recursively. It returns the registered flag values. As in Register, map elements and slice elements have flags only if present at registration.
+//
+// In addition to names and usage messages, flags shorthands are read from 'short' tag, flags hidden from help are marked with 'hidden' tag set to true and deprecated flags are marked with 'deprecated' tag holding deprecation message. Boolean flags do not require a value. No flag is registered if any of the names or shorthands collides with another one or with a flag already defined in the flag set.
+func RegisterPFlags(fs *pflag.FlagSet, base any, opts ...Option) ([]*Value, error) {
+	o := newOptions(opts)
+	values := o.recursive(base)
+	flags := make([]*pflag.Flag, len(values))
+	marks := make([]pflagMarks, len(values))
+	seenNames := make(map[string]bool, len(values))
+	seenShorthands := map[string]bool{}
+	for i, val := range values {
+		pf := &pflag.Flag{
+			Name:      o.name(val),
+			Shorthand: lastTag(val.Path(), "short"),
+			Usage:     o.usage(val),
+			Value:     val,
+			DefValue:  val.String(),
+		}
+		if val.IsBoolFlag() {
+			pf.NoOptDefVal = "true"
+		}
+		if seenNames[pf.Name] || fs.Lookup(pf.Name) != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFlagRedefined, pf.Name)
+		}
+		seenNames[pf.Name] = true
+		if pf.Shorthand != "" {
+			if len(pf.Shorthand) != 1 || pf.Shorthand[0] > 0x7f {
+				return nil, fmt.Errorf("%w: %q for flag %s", ErrInvalidShorthand, pf.Shorthand, pf.Name)
+			}
+			if seenShorthands[pf.Shorthand] || fs.ShorthandLookup(pf.Shorthand) != nil {
+				return nil, fmt.Errorf("%w: shorthand %s for flag %s", ErrFlagRedefined, pf.Shorthand, pf.Name)
+			}
+			seenShorthands[pf.Shorthand] = true
+		}
+		m, err := newPFlagMarks(val.Path())
+		if err != nil {
+			return nil, fmt.Errorf("flag %s: %w", pf.Name, err)
+		}
+		flags[i], marks[i] = pf, m
+	}
+	for i, pf := range flags {
+		values[i].flagName = "--" + pf.Name
+		values[i].recordDefault()
+		fs.AddFlag(pf)
+		if err := marks[i].apply(fs, pf.Name); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+type pflagMarks struct {
+	hidden     bool
+	deprecated string
+}
+
+func newPFlagMarks(path []reflect.StructField) (m pflagMarks, err error) {
+	if v := lastTag(path, "hidden"); v != "" {
+		if m.hidden, err = strconv.ParseBool(v); err != nil {
+			return m, fmt.Errorf("parsing hidden tag: %w", err)
+		}
+	}
+	m.deprecated = lastTag(path, "deprecated")
+	return m, nil
+}
+
+func (m pflagMarks) apply(fs *pflag.FlagSet, name string) error {
+	if m.hidden {
+		if err := fs.MarkHidden(name); err != nil {
+			return err
+		}
+	}
+	if m.deprecated != "" {
+		if err := fs.MarkDeprecated(name, m.deprecated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
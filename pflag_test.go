@@ -0,0 +1,133 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestRegisterPFlagsBase struct {
+	Verbose bool   `json:"verbose" short:"v"`
+	Name    string `json:"name" short:"n" usage:"name usage"`
+	Secret  string `json:"secret" hidden:"true"`
+	Old     string `json:"old" deprecated:"use name instead"`
+}
+
+func TestRegisterPFlags(t *testing.T) {
+	t.Parallel()
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	given := &TestRegisterPFlagsBase{Name: "default"}
+
+	values, err := jsonflag.RegisterPFlags(fs, given, jsonflag.WithFilters(func(val *jsonflag.Value) jsonflag.FilterResult {
+		if len(val.Path()) == 0 {
+			return jsonflag.SkipAndDescend
+		}
+		return jsonflag.IncludeAndDescend
+	}))
+	require.NoError(t, err)
+	require.Len(t, values, 4)
+
+	verbose := fs.Lookup("verbose")
+	require.Equal(t, "v", verbose.Shorthand)
+	require.Equal(t, "true", verbose.NoOptDefVal)
+
+	name := fs.Lookup("name")
+	require.Equal(t, "n", name.Shorthand)
+	require.Equal(t, "name usage", name.Usage)
+	require.Equal(t, "default", name.DefValue)
+	require.Empty(t, name.NoOptDefVal)
+
+	require.True(t, fs.Lookup("secret").Hidden)
+	require.True(t, fs.Lookup("old").Hidden)
+	require.Equal(t, "use name instead", fs.Lookup("old").Deprecated)
+
+	require.NoError(t, fs.Parse([]string{"-v", "-n", "foo", "--secret=bar", "--old=baz"}))
+	require.Equal(t, &TestRegisterPFlagsBase{Verbose: true, Name: "foo", Secret: "bar", Old: "baz"}, given)
+}
+
+func TestRegisterPFlagsErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		given     any
+		predefine func(fs *pflag.FlagSet)
+		wantIs    error
+		wantError string
+	}{
+		{
+			name:      "name-collision",
+			given:     &struct{ Foo string }{},
+			predefine: func(fs *pflag.FlagSet) { fs.String("Foo", "", "") },
+			wantIs:    jsonflag.ErrFlagRedefined,
+			wantError: "jsonflag: flag redefined: Foo",
+		},
+		{
+			name: "shorthand-collision-with-defined-flag",
+			given: &struct {
+				Foo string `short:"f"`
+			}{},
+			predefine: func(fs *pflag.FlagSet) { fs.StringP("bar", "f", "", "") },
+			wantIs:    jsonflag.ErrFlagRedefined,
+			wantError: "jsonflag: flag redefined: shorthand f for flag Foo",
+		},
+		{
+			name: "shorthand-collision-between-values",
+			given: &struct {
+				Foo string `short:"f"`
+				Bar string `short:"f"`
+			}{},
+			wantIs:    jsonflag.ErrFlagRedefined,
+			wantError: "jsonflag: flag redefined: shorthand f for flag Bar",
+		},
+		{
+			name: "invalid-shorthand",
+			given: &struct {
+				Foo string `short:"foo"`
+			}{},
+			wantIs:    jsonflag.ErrInvalidShorthand,
+			wantError: `jsonflag: invalid flag shorthand: "foo" for flag Foo`,
+		},
+		{
+			name: "invalid-hidden-tag",
+			given: &struct {
+				Foo string `hidden:"maybe"`
+			}{},
+			wantError: `flag Foo: parsing hidden tag: strconv.ParseBool: parsing "maybe": invalid syntax`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+			if test.predefine != nil {
+				test.predefine(fs)
+			}
+			values, err := jsonflag.RegisterPFlags(fs, test.given)
+			if test.wantIs != nil {
+				require.ErrorIs(t, err, test.wantIs)
+			}
+			require.EqualError(t, err, test.wantError)
+			require.Nil(t, values)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownFormat is returned when printing config in an unknown format.
+var ErrUnknownFormat = errors.New("jsonflag: unknown format")
+
+// Format is an output format of PrintConfig.
+type Format int
+
+const (
+	FormatJSON         Format = iota // compact JSON
+	FormatIndentedJSON               // indented JSON, with annotations as comments (see JSONC)
+	FormatEnv                        // environment variables lines (quoted for POSIX shells), with names as in ApplyEnv (see WithEnv for prefix)
+	FormatArgs                       // command line arguments, one per line (quoted for POSIX shells), with names as in Register
+)
+
+// WithAnnotations makes PrintConfig annotate each printed value with its default and, if changed, the source that set it.
+func WithAnnotations() Option {
+	return func(o *options) {
+		o.annotate = true
+	}
+}
+
+// PrintConfig writes the effective config, ie. the value the provided flag values belong to (after all sets), in the provided format. Secrets are redacted (see Value.String). Env and command line arguments formats contain only leaf values, ie. values without any other values within. Leaves with empty string form (zero values) are omitted, as well as empty slices and maps. In env format, slices with more than one element and no separator (see Value.SetSeparator) are omitted too, as they cannot be set with a single environment variable.
+func PrintConfig(w io.Writer, values []*Value, format Format, opts ...Option) error {
+	o := newOptions(opts)
+	switch format {
+	case FormatJSON, FormatIndentedJSON:
+		return printJSON(w, o, values, format == FormatIndentedJSON)
+	case FormatEnv:
+		return printLeaves(w, o, values, true, func(val *Value, s string) string {
+			if name := envName(o, val, o.envPrefix); name != "" {
+				return name + "=" + quoteShell(s)
+			}
+			return ""
+		})
+	case FormatArgs:
+		return printLeaves(w, o, values, false, func(val *Value, s string) string {
+			return quoteShell("--" + o.name(val) + "=" + s)
+		})
+	}
+	return fmt.Errorf("%w: %d", ErrUnknownFormat, format)
+}
+
+func printJSON(w io.Writer, o *options, values []*Value, indent bool) error {
+	root := rootOf(values)
+	if root == nil {
+		return nil
+	}
+	b, err := json.Marshal(root.Interface())
+	if err != nil {
+		return err
+	}
+	if b, err = redactJSON(b, root.Type()); err != nil {
+		return err
+	}
+	if indent {
+		buf := bytes.Buffer{}
+		if err := json.Indent(&buf, b, "", "  "); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	}
+	if indent && o.annotate {
+		b = annotateJSON(b, values)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// rootOf returns the value that all the provided flag values belong to.
+func rootOf(values []*Value) *reflect.Value {
+	for _, val := range values {
+		if val.isInitialized() && val.entry == nil {
+			return &val.base
+		}
+	}
+	return nil
+}
+
+// annotateJSON adds annotations of leaf values as comments at the end of lines with their keys.
+func annotateJSON(b []byte, values []*Value) []byte {
+	lines := bytes.Split(b, []byte{'\n'})
+	keyLines := configLines(b)
+	for _, val := range leaves(values) {
+		if l, ok := keyLines[JSONName(val.Path())]; ok && l > 0 {
+			lines[l-1] = append(lines[l-1], " // "+annotation(val)...)
+		}
+	}
+	return bytes.Join(lines, []byte{'\n'})
+}
+
+func printLeaves(w io.Writer, o *options, values []*Value, joined bool, format func(*Value, string) string) error {
+	for _, val := range leaves(values) {
+		for _, s := range leafStrings(val, joined) {
+			line := format(val, s)
+			if line == "" {
+				continue
+			}
+			if o.annotate {
+				line += " # " + annotation(val)
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// leaves returns flag values without any other flag values within.
+func leaves(values []*Value) []*Value {
+	leaves := []*Value(nil)
+	for _, val := range values {
+		if !slices.ContainsFunc(values, func(other *Value) bool { return contains(val, other) }) {
+			leaves = append(leaves, val)
+		}
+	}
+	return leaves
+}
+
+// contains reports whether the other flag value is a value within the flag value, based on their paths.
+func contains(val, other *Value) bool {
+	path, otherPath := val.Path(), other.Path()
+	if len(path) >= len(otherPath) {
+		return false
+	}
+	for i := range path {
+		if path[i].Name != otherPath[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// leafStrings returns inputs that set the value to its current content, one per set. Unless joined, slice elements are set one by one. Otherwise, slices are set at once, which is possible only with a separator (see Value.SetSeparator) or up to one element.
+func leafStrings(val *Value, joined bool) []string {
+	if !val.isSlice || val.decodeFn != nil || joined && val.separator != 0 {
+		if s := val.String(); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	val.load()
+	v := elemIfPtr(val.get())
+	if joined && v.Len() > 1 {
+		return nil
+	}
+	elems := []string(nil)
+	for i := range v.Len() {
+		elems = append(elems, val.elemValue(addressable(v.Index(i)), i).String())
+	}
+	return elems
+}
+
+func annotation(val *Value) string {
+	sources := val.Sources()
+	a := "default " + strconv.Quote(sources[0].Input)
+	if len(sources) > 1 {
+		a += ", changed by " + sources[len(sources)-1].String()
+	}
+	return a
+}
+
+// quoteShell quotes the provided string with single quotes for POSIX shells, unless it is safe as is. Single quoted strings have no escapes nor expansions.
+func quoteShell(s string) string {
+	if isShellSafe(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func isShellSafe(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:,=@%+", r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+func TestPrintConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		format jsonflag.Format
+		opts   []jsonflag.Option
+		want   string
+	}{
+		{
+			name:   "json",
+			format: jsonflag.FormatJSON,
+			want:   "{\"host\":\"it's me\",\"port\":80,\"tags\":[\"a\",\"b c\"],\"limits\":{\"cpu\":2},\"servers\":[{\"Value\":\"x\"}],\"labels\":null}\n",
+		},
+		{
+			name:   "indented-json",
+			format: jsonflag.FormatIndentedJSON,
+			want:   "{\n  \"host\": \"it's me\",\n  \"port\": 80,\n  \"tags\": [\n    \"a\",\n    \"b c\"\n  ],\n  \"limits\": {\n    \"cpu\": 2\n  },\n  \"servers\": [\n    {\n      \"Value\": \"x\"\n    }\n  ],\n  \"labels\": null\n}\n",
+		},
+		{
+			name:   "indented-json/annotated",
+			format: jsonflag.FormatIndentedJSON,
+			opts:   []jsonflag.Option{jsonflag.WithAnnotations()},
+			want:   "{\n  \"host\": \"it's me\", // default \"localhost\", changed by flag -host\n  \"port\": 80, // default \"80\"\n  \"tags\": [ // default \"\", changed by flag -tags\n    \"a\",\n    \"b c\"\n  ],\n  \"limits\": { // default \"\", changed by flag -limits\n    \"cpu\": 2\n  },\n  \"servers\": [ // default \"\", changed by flag -servers\n    {\n      \"Value\": \"x\"\n    }\n  ],\n  \"labels\": null // default \"\"\n}\n",
+		},
+		{
+			name:   "env",
+			format: jsonflag.FormatEnv,
+			opts:   []jsonflag.Option{jsonflag.WithEnv("APP")},
+			want:   "APP_HOST='it'\\''s me'\nAPP_PORT=80\nAPP_LIMITS='{\"cpu\":2}'\nAPP_SERVERS='{\"Value\":\"x\"}'\n",
+		},
+		{
+			name:   "env/annotated",
+			format: jsonflag.FormatEnv,
+			opts:   []jsonflag.Option{jsonflag.WithEnv("APP"), jsonflag.WithAnnotations()},
+			want:   "APP_HOST='it'\\''s me' # default \"localhost\", changed by flag -host\nAPP_PORT=80 # default \"80\"\nAPP_LIMITS='{\"cpu\":2}' # default \"\", changed by flag -limits\nAPP_SERVERS='{\"Value\":\"x\"}' # default \"\", changed by flag -servers\n",
+		},
+		{
+			name:   "args",
+			format: jsonflag.FormatArgs,
+			want:   "'--host=it'\\''s me'\n--port=80\n--tags=a\n'--tags=b c'\n'--limits={\"cpu\":2}'\n'--servers={\"Value\":\"x\"}'\n",
+		},
+		{
+			name:   "args/annotated",
+			format: jsonflag.FormatArgs,
+			opts:   []jsonflag.Option{jsonflag.WithAnnotations()},
+			want:   "'--host=it'\\''s me' # default \"localhost\", changed by flag -host\n--port=80 # default \"80\"\n--tags=a # default \"\", changed by flag -tags\n'--tags=b c' # default \"\", changed by flag -tags\n'--limits={\"cpu\":2}' # default \"\", changed by flag -limits\n'--servers={\"Value\":\"x\"}' # default \"\", changed by flag -servers\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			given := &TestLoadBase{Host: "localhost", Port: 80}
+			args := []string{"--host=it's me", "--tags=a", "--tags=b c", "--limits=cpu=2", `--servers={"Value":"x"}`}
+			values, err := jsonflag.Load(fs, given, args)
+			require.NoError(t, err)
+
+			b := &strings.Builder{}
+			require.NoError(t, jsonflag.PrintConfig(b, values, test.format, test.opts...))
+			require.Equal(t, test.want, b.String())
+		})
+	}
+}
+
+func TestPrintConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	given := &TestLoadBase{Host: "localhost", Port: 80}
+	values, err := jsonflag.Load(fs, given, []string{"--host=it's me", "--tags=a", "--tags=b c", "--limits=cpu=2"})
+	require.NoError(t, err)
+
+	b := &strings.Builder{}
+	require.NoError(t, jsonflag.PrintConfig(b, values, jsonflag.FormatIndentedJSON, jsonflag.WithAnnotations()))
+	got := &TestLoadBase{}
+	require.NoError(t, jsonflag.JSONC{}.Decode([]byte(b.String()), got))
+	require.Equal(t, given, got)
+
+	b.Reset()
+	require.NoError(t, jsonflag.PrintConfig(b, values, jsonflag.FormatArgs))
+	got = &TestLoadBase{}
+	_, err = jsonflag.Load(flag.NewFlagSet("", flag.ContinueOnError), got, unquoteArgs(b.String()))
+	require.NoError(t, err)
+	require.Equal(t, given, got)
+}
+
+func TestPrintConfigArgsRoundTrip(t *testing.T) {
+	t.Parallel()
+	type Base struct {
+		Times     []time.Time      `json:"times" layout:"2006-01-02"`
+		Durations []time.Duration  `json:"durations"`
+		Window    [2]time.Duration `json:"window"`
+		Dates     [1]time.Time     `json:"dates" layout:"2006-01-02"`
+		Points    [2]complex128    `json:"points"`
+	}
+	given := &Base{
+		Times:     []time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		Durations: []time.Duration{time.Second, time.Minute},
+		Window:    [2]time.Duration{time.Second, 2 * time.Second},
+		Dates:     [1]time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Points:    [2]complex128{1 + 2i, 3},
+	}
+	values, err := jsonflag.Load(flag.NewFlagSet("", flag.ContinueOnError), given, nil)
+	require.NoError(t, err)
+
+	b := &strings.Builder{}
+	require.NoError(t, jsonflag.PrintConfig(b, values, jsonflag.FormatArgs))
+	require.Contains(t, b.String(), "--times=2024-01-02\n")
+	got := &Base{}
+	_, err = jsonflag.Load(flag.NewFlagSet("", flag.ContinueOnError), got, unquoteArgs(b.String()))
+	require.NoError(t, err)
+	require.Equal(t, given, got)
+}
+
+func TestPrintConfigEnvQuoting(t *testing.T) {
+	t.Parallel()
+	given := &struct {
+		Name string `json:"name"`
+	}{Name: `a b$HOME "c"`}
+	values := jsonflag.Recursive(given)
+
+	b := &strings.Builder{}
+	require.NoError(t, jsonflag.PrintConfig(b, values, jsonflag.FormatEnv, jsonflag.WithEnv("APP")))
+	require.Equal(t, "APP_NAME='a b$HOME \"c\"'\n", b.String())
+}
+
+// unquoteArgs returns arguments printed with FormatArgs, one per line.
+func unquoteArgs(s string) []string {
+	args := []string(nil)
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if unquoted, ok := strings.CutPrefix(line, "'"); ok {
+			line = strings.ReplaceAll(strings.TrimSuffix(unquoted, "'"), `'\''`, "'")
+		}
+		args = append(args, line)
+	}
+	return args
+}
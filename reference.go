@@ -0,0 +1,118 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrUnresolvedReference is returned when a value reference cannot be resolved.
+var ErrUnresolvedReference = errors.New("jsonflag: unresolved reference")
+
+// Resolver returns content of the provided reference, with the prefix the resolver is registered under removed (see WithResolver).
+type Resolver func(ref string) (string, error)
+
+// FileResolver returns resolver reading content of the file under the referenced path, or of the provided reader (usually os.Stdin) if the path is "-". A single trailing new line is removed from the content. By default, it is registered under prefix "@" (see WithReferences).
+func FileResolver(stdin io.Reader) Resolver {
+	return func(ref string) (string, error) {
+		var b []byte
+		var err error
+		if ref == "-" {
+			b, err = io.ReadAll(stdin)
+		} else {
+			b, err = os.ReadFile(ref)
+		}
+		if err != nil {
+			return "", err
+		}
+		s := strings.TrimSuffix(string(b), "\n")
+		return strings.TrimSuffix(s, "\r"), nil
+	}
+}
+
+// EnvResolver returns resolver reading the referenced environment variable with the provided lookup function (usually os.LookupEnv). By default, it is registered under prefix "env:" (see WithReferences).
+func EnvResolver(lookupEnv func(string) (string, bool)) Resolver {
+	return func(ref string) (string, error) {
+		s, ok := lookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrUnresolvedReference, ref)
+		}
+		return s, nil
+	}
+}
+
+// WithReferences allows inputs of all registered flag values to be references, resolved before parsing. By default, references to files ("@path", with "@-" being standard input) and environment variables ("env:NAME") are resolved (see WithResolver). Independently of the option, references can be allowed or disallowed for a single field with 'ref' tag, eg. `ref:"true"` or `ref:"false"`, so that literal values starting with a reference prefix can still be passed.
+func WithReferences() Option {
+	return func(o *options) {
+		o.references = true
+	}
+}
+
+// WithResolver registers resolver of references starting with the provided prefix (eg. "@" or "vault:"), replacing any resolver already registered under the prefix. When multiple prefixes match, the longest one is used. It does not allow references on its own (see WithReferences).
+func WithResolver(prefix string, r Resolver) Option {
+	return func(o *options) {
+		if o.resolvers == nil {
+			o.resolvers = map[string]Resolver{}
+		}
+		o.resolvers[prefix] = r
+	}
+}
+
+// setResolvers sets resolver of references for all flag values with references allowed.
+func (o *options) setResolvers(values []*Value) {
+	resolvers := map[string]Resolver{
+		"@":    FileResolver(os.Stdin),
+		"env:": EnvResolver(o.lookupEnv),
+	}
+	for prefix, r := range o.resolvers {
+		resolvers[prefix] = r
+	}
+	resolve := resolveReference(resolvers)
+	for _, val := range values {
+		allowed := o.references
+		if tag, ok := lastTagLookup(val.Path(), "ref"); ok {
+			allowed, _ = strconv.ParseBool(tag)
+		}
+		if allowed {
+			val.SetResolver(resolve)
+		}
+	}
+}
+
+// resolveReference returns function resolving the provided input with the resolver registered under the longest matching prefix. Inputs without matching prefix are returned unchanged.
+func resolveReference(resolvers map[string]Resolver) func(string) (string, error) {
+	return func(s string) (string, error) {
+		prefix := ""
+		var resolver Resolver
+		for p, r := range resolvers {
+			if strings.HasPrefix(s, p) && (resolver == nil || len(p) > len(prefix)) {
+				prefix, resolver = p, r
+			}
+		}
+		if resolver == nil {
+			return s, nil
+		}
+		resolved, err := resolver(strings.TrimPrefix(s, prefix))
+		if err != nil {
+			return "", fmt.Errorf("reference %q: %w", s, err)
+		}
+		return resolved, nil
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestReferenceBase struct {
+	Password string            `json:"password"`
+	Handle   string            `json:"handle" ref:"false"`
+	Cert     string            `json:"cert" ref:"true"`
+	Limits   map[string]int    `json:"limits"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "password"), []byte("hunter2\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "limits"), []byte(`{"cpu": 2}`), 0o600))
+	lookupEnv := func(name string) (string, bool) {
+		v, ok := map[string]string{"DB_PASSWORD": "from-env"}[name]
+		return v, ok
+	}
+	tests := []struct {
+		name      string
+		opts      []jsonflag.Option
+		args      []string
+		want      *TestReferenceBase
+		wantError string
+	}{
+		{
+			name: "disabled",
+			args: []string{"--password=@" + filepath.Join(dir, "password"), "--handle=@me"},
+			want: &TestReferenceBase{Password: "@" + filepath.Join(dir, "password"), Handle: "@me"},
+		},
+		{
+			name: "file",
+			opts: []jsonflag.Option{jsonflag.WithReferences()},
+			args: []string{"--password=@" + filepath.Join(dir, "password"), "--limits=@" + filepath.Join(dir, "limits")},
+			want: &TestReferenceBase{Password: "hunter2", Limits: map[string]int{"cpu": 2}},
+		},
+		{
+			name: "stdin",
+			opts: []jsonflag.Option{jsonflag.WithReferences(), jsonflag.WithResolver("@", jsonflag.FileResolver(strings.NewReader("cert\n")))},
+			args: []string{"--cert=@-"},
+			want: &TestReferenceBase{Cert: "cert"},
+		},
+		{
+			name: "env",
+			opts: []jsonflag.Option{jsonflag.WithReferences(), jsonflag.WithLookupEnv(lookupEnv)},
+			args: []string{"--password=env:DB_PASSWORD"},
+			want: &TestReferenceBase{Password: "from-env"},
+		},
+		{
+			name: "tags",
+			args: []string{"--handle=@me", "--cert=@" + filepath.Join(dir, "password")},
+			want: &TestReferenceBase{Handle: "@me", Cert: "hunter2"},
+		},
+		{
+			name: "tags-with-option",
+			opts: []jsonflag.Option{jsonflag.WithReferences()},
+			args: []string{"--handle=@me", "--cert=@" + filepath.Join(dir, "password")},
+			want: &TestReferenceBase{Handle: "@me", Cert: "hunter2"},
+		},
+		{
+			name: "custom-resolver",
+			opts: []jsonflag.Option{
+				jsonflag.WithReferences(),
+				jsonflag.WithResolver("vault:", func(ref string) (string, error) { return "vault-" + ref, nil }),
+				jsonflag.WithResolver("vault:kv/", func(ref string) (string, error) { return ref + "=kv", nil }),
+			},
+			args: []string{"--password=vault:db", "--labels=vault:kv/labels"},
+			want: &TestReferenceBase{Password: "vault-db", Labels: map[string]string{"labels": "kv"}},
+		},
+		{
+			name:      "missing-file",
+			opts:      []jsonflag.Option{jsonflag.WithReferences()},
+			args:      []string{"--password=@" + filepath.Join(dir, "missing")},
+			wantError: `invalid value "@` + filepath.Join(dir, "missing") + `" for flag -password: reference "@` + filepath.Join(dir, "missing") + `": open ` + filepath.Join(dir, "missing") + `: no such file or directory`,
+		},
+		{
+			name:      "missing-env",
+			opts:      []jsonflag.Option{jsonflag.WithReferences(), jsonflag.WithLookupEnv(lookupEnv)},
+			args:      []string{"--password=env:MISSING"},
+			wantError: `invalid value "env:MISSING" for flag -password: reference "env:MISSING": jsonflag: unresolved reference: environment variable MISSING is not set`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			given := &TestReferenceBase{}
+			_, err := jsonflag.Register(fs, given, test.opts...)
+			require.NoError(t, err)
+			err = fs.Parse(test.args)
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, given)
+		})
+	}
+}
+
+func TestReferencesSource(t *testing.T) {
+	t.Parallel()
+	lookupEnv := func(name string) (string, bool) {
+		v, ok := map[string]string{"APP_PASSWORD": "env:DB_PASSWORD", "DB_PASSWORD": "from-env"}[name]
+		return v, ok
+	}
+	given := &TestReferenceBase{}
+	values := jsonflag.Recursive(given)
+	require.NoError(t, jsonflag.ApplyEnv(values, "APP", jsonflag.WithReferences(), jsonflag.WithLookupEnv(lookupEnv)))
+	require.Equal(t, "from-env", given.Password)
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceEnv, Name: "APP_PASSWORD", Input: "env:DB_PASSWORD"}, values[1].Source())
+}
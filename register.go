@@ -0,0 +1,159 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// ErrFlagRedefined is returned when registering a flag under a name that is already in use.
+var ErrFlagRedefined = errors.New("jsonflag: flag redefined")
+
+// Option configures how flag values are found and registered.
+type Option func(*options)
+
+type options struct {
+	nameFn    func([]reflect.StructField) string
+	caseFn    func(string) string
+	usageFn   func([]reflect.StructField) string
+	filters   []FilterFunc
+	lookupEnv func(string) (string, bool)
+	decodeFn  func([]byte, any) error
+
+	configFlag string
+	useEnv     bool
+	envPrefix  string
+	annotate   bool
+	references bool
+	resolvers  map[string]Resolver
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		nameFn:     JSONName,
+		usageFn:    Usage,
+		lookupEnv:  os.LookupEnv,
+		configFlag: "config",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// recursive returns flag values for the provided value and all values within (see Recursive), configured according to the options.
+func (o *options) recursive(base any) []*Value {
+	values := Recursive(base, o.filters...)
+	if o.decodeFn != nil {
+		for _, val := range values {
+			val.SetJSONDecoder(o.decodeFn)
+		}
+	}
+	o.setResolvers(values)
+	linkChildren(values)
+	return values
+}
+
+// decodeJSON decodes JSON input with the configured JSON decoder.
+func (o *options) decodeJSON(data []byte, v any) error {
+	if o.decodeFn != nil {
+		return o.decodeFn(data, v)
+	}
+	return unmarshalJSON(data, v)
+}
+
+func (o *options) name(val *Value) string {
+	n := o.nameFn(val.Path())
+	if o.caseFn != nil {
+		n = o.caseFn(n)
+	}
+	return n
+}
+
+func (o *options) usage(val *Value) string {
+	return o.usageFn(val.Path())
+}
+
+// WithName sets the function used to create flag names from flag values paths, eg. Name or JSONName. By default JSONName is used.
+func WithName(fn func([]reflect.StructField) string) Option {
+	return func(o *options) {
+		o.nameFn = fn
+	}
+}
+
+// WithCase sets the function used to convert flag names created by the name function, eg. JsonCamelCase, SnakeCase or DashCase. By default flag names are left unchanged.
+func WithCase(fn func(string) string) Option {
+	return func(o *options) {
+		o.caseFn = fn
+	}
+}
+
+// WithUsage sets the function used to create flag usage messages from flag values paths. By default Usage is used.
+func WithUsage(fn func([]reflect.StructField) string) Option {
+	return func(o *options) {
+		o.usageFn = fn
+	}
+}
+
+// WithFilters adds filters used to decide which flag values should be registered (see Recursive).
+func WithFilters(filters ...FilterFunc) Option {
+	return func(o *options) {
+		o.filters = append(o.filters, filters...)
+	}
+}
+
+// WithJSONDecoder sets the function used to decode JSON input of flag values (see Value.SetJSONDecoder) and config files (see Load), eg. JSONC.Decode. By default package "encoding/json" is used.
+func WithJSONDecoder(fn func([]byte, any) error) Option {
+	return func(o *options) {
+		o.decodeFn = fn
+	}
+}
+
+// Register registers in the provided flag set flag values for the provided value and all values within, recursively. It returns the registered flag values. Map elements and slice elements have flags only if present at registration (see ExpandMaps and ExpandSlices) - use Load to define flags also for new ones. No flag is registered if any of the names collides with another one or with a flag already defined in the flag set.
+func Register(fs *flag.FlagSet, base any, opts ...Option) ([]*Value, error) {
+	o := newOptions(opts)
+	values := o.recursive(base)
+	err := register(fs, o, values, func(val *Value, name string) flag.Value {
+		val.flagName = "-" + name
+		return val
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// register registers in the provided flag set the provided flag values, wrapped with the provided function.
+func register(fs *flag.FlagSet, o *options, values []*Value, wrap func(val *Value, name string) flag.Value) error {
+	names := make([]string, len(values))
+	seen := make(map[string]bool, len(values))
+	for i, val := range values {
+		n := o.name(val)
+		if seen[n] || fs.Lookup(n) != nil {
+			return fmt.Errorf("%w: %s", ErrFlagRedefined, n)
+		}
+		seen[n] = true
+		names[i] = n
+	}
+	for i, val := range values {
+		val.recordDefault()
+		fs.Var(wrap(val, names[i]), names[i], o.usage(val))
+	}
+	return nil
+}
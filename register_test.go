@@ -0,0 +1,167 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestRegisterBase struct {
+	FooBar string `json:"fooBar" usage:"foo bar usage"`
+	Nested struct {
+		BazQux int `json:"bazQux"`
+	} `json:"nested"`
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		opts      []jsonflag.Option
+		predefine string
+		args      []string
+		wantNames []string
+		wantError string
+		want      *TestRegisterBase
+	}{
+		{
+			name:      "defaults",
+			args:      []string{"--fooBar=foo", "--nested.bazQux=2"},
+			wantNames: []string{"input", "fooBar", "nested", "nested.bazQux"},
+			want: &TestRegisterBase{FooBar: "foo", Nested: struct {
+				BazQux int `json:"bazQux"`
+			}{BazQux: 2}},
+		},
+		{
+			name:      "name-and-case",
+			opts:      []jsonflag.Option{jsonflag.WithName(jsonflag.Name), jsonflag.WithCase(jsonflag.DashCase)},
+			args:      []string{"--foo-bar=foo", "--nested.baz-qux=2"},
+			wantNames: []string{"input", "foo-bar", "nested", "nested.baz-qux"},
+			want: &TestRegisterBase{FooBar: "foo", Nested: struct {
+				BazQux int `json:"bazQux"`
+			}{BazQux: 2}},
+		},
+		{
+			name: "filters",
+			opts: []jsonflag.Option{jsonflag.WithFilters(func(val *jsonflag.Value) jsonflag.FilterResult {
+				if len(val.Path()) == 0 {
+					return jsonflag.SkipAndDescend
+				}
+				return jsonflag.IncludeNoDescend
+			})},
+			args:      []string{"--fooBar=foo"},
+			wantNames: []string{"fooBar", "nested"},
+			want:      &TestRegisterBase{FooBar: "foo"},
+		},
+		{
+			name:      "collision-with-defined-flag",
+			predefine: "fooBar",
+			wantError: "flag redefined: fooBar",
+		},
+		{
+			name:      "collision-between-values",
+			opts:      []jsonflag.Option{jsonflag.WithName(func([]reflect.StructField) string { return "same" })},
+			wantError: "flag redefined: same",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			if test.predefine != "" {
+				fs.String(test.predefine, "", "")
+			}
+			given := &TestRegisterBase{}
+
+			values, err := jsonflag.Register(fs, given, test.opts...)
+			if test.wantError != "" {
+				require.ErrorIs(t, err, jsonflag.ErrFlagRedefined)
+				require.EqualError(t, err, "jsonflag: "+test.wantError)
+				require.Nil(t, values)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, values, len(test.wantNames))
+
+			gotNames := []string{}
+			fs.VisitAll(func(f *flag.Flag) { gotNames = append(gotNames, f.Name) })
+			require.ElementsMatch(t, test.wantNames, gotNames)
+
+			require.NoError(t, fs.Parse(test.args))
+			require.Equal(t, test.want, given)
+		})
+	}
+}
+
+func TestRegisterUsage(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	_, err := jsonflag.Register(fs, &TestRegisterBase{}, jsonflag.WithUsage(func(path []reflect.StructField) string {
+		return strings.ToUpper(jsonflag.Usage(path))
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "FOO BAR USAGE", fs.Lookup("fooBar").Usage)
+}
+
+func TestRegisterElems(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		args      []string
+		wantError string
+		want      []TestLoadElemsServer
+	}{
+		{
+			name: "present",
+			args: []string{"--servers.0.host=x"},
+			want: []TestLoadElemsServer{{Host: "x", Port: 80}},
+		},
+		{
+			name:      "past-the-end", // flags are defined only for elements present at registration (see Load)
+			args:      []string{"--servers.0.host=x", "--servers.1.host=y"},
+			wantError: "flag provided but not defined: -servers.1.host",
+			want:      []TestLoadElemsServer{{Host: "x", Port: 80}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			given := &TestLoadElemsBase{Servers: []TestLoadElemsServer{{Host: "default", Port: 80}}}
+			_, err := jsonflag.Register(fs, given, jsonflag.WithFilters(jsonflag.ExpandSlices))
+			require.NoError(t, err)
+
+			err = fs.Parse(test.args)
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.want, given.Servers)
+		})
+	}
+}
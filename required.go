@@ -0,0 +1,81 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RequiredError is returned when required values have not been provided. It lists all of them.
+type RequiredError struct {
+	Missing []MissingValue
+}
+
+// MissingValue describes a required value that has not been provided.
+type MissingValue struct {
+	Path []reflect.StructField // path of the value (see Value.Path)
+	Flag string                // flag name of the value, without dashes
+	Env  string                // environment variable name of the value, if enabled (see WithEnv)
+}
+
+func (e *RequiredError) Error() string {
+	names := make([]string, len(e.Missing))
+	for i, m := range e.Missing {
+		names[i] = m.Flag
+		if m.Env != "" {
+			names[i] += " (env " + m.Env + ")"
+		}
+	}
+	return "jsonflag: missing required values: " + strings.Join(names, ", ")
+}
+
+// CheckRequired reports, as RequiredError, all flag values with `required:"true"` tag that have not been provided. A value is provided if any source has set it (see Value.Sources), or if a value it is within has been set and the value is not zero (eg. a struct field present in JSON of the struct). Names are created as in Register and, with WithEnv, as in ApplyEnv.
+func CheckRequired(values []*Value, opts ...Option) error {
+	o := newOptions(opts)
+	missing := []MissingValue(nil)
+	for _, val := range values {
+		if required, _ := strconv.ParseBool(lastTag(val.Path(), "required")); !required || isProvided(val, values) {
+			continue
+		}
+		m := MissingValue{Path: val.Path(), Flag: o.name(val)}
+		if o.useEnv {
+			m.Env = envName(o, val, o.envPrefix)
+		}
+		missing = append(missing, m)
+	}
+	if len(missing) > 0 {
+		return &RequiredError{Missing: missing}
+	}
+	return nil
+}
+
+func isProvided(val *Value, values []*Value) bool {
+	if val.isSet() {
+		return true
+	}
+	for _, other := range values {
+		if contains(other, val) && other.isSet() {
+			return !val.isZero()
+		}
+	}
+	return false
+}
+
+// isSet reports if any source has set the value.
+func (val *Value) isSet() bool {
+	return len(val.sources) > 1
+}
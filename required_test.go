@@ -0,0 +1,140 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestRequiredBase struct {
+	DB struct {
+		DSN  string `json:"dsn" required:"true"`
+		Pool int    `json:"pool"`
+	} `json:"db"`
+	Token string `json:"token" required:"true" env:"TOKEN"`
+	Port  int    `json:"port" required:"true"`
+}
+
+func TestCheckRequired(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		file        string
+		env         map[string]string
+		args        []string
+		opts        []jsonflag.Option
+		wantMissing []string
+		wantError   string
+	}{
+		{
+			name:        "none",
+			wantMissing: []string{"db.dsn", "token", "port"},
+			wantError:   "jsonflag: missing required values: db.dsn, token, port",
+		},
+		{
+			name:        "none-with-env",
+			opts:        []jsonflag.Option{jsonflag.WithEnv("APP")},
+			wantMissing: []string{"db.dsn", "token", "port"},
+			wantError:   "jsonflag: missing required values: db.dsn (env APP_DB_DSN), token (env TOKEN), port (env APP_PORT)",
+		},
+		{
+			name:        "none-with-env-and-case-function",
+			opts:        []jsonflag.Option{jsonflag.WithEnv("APP"), jsonflag.WithCase(jsonflag.DashCase)},
+			wantMissing: []string{"db.dsn", "token", "port"},
+			wantError:   "jsonflag: missing required values: db.dsn (env APP_DB_DSN), token (env TOKEN), port (env APP_PORT)",
+		},
+		{
+			name:        "flags",
+			args:        []string{"--db.dsn=postgres://", "--port=0"},
+			wantMissing: []string{"token"},
+			wantError:   "jsonflag: missing required values: token",
+		},
+		{
+			name: "all-sources",
+			file: `{"db": {"dsn": "postgres://"}}`,
+			env:  map[string]string{"TOKEN": "token"},
+			args: []string{"--port=80"},
+			opts: []jsonflag.Option{jsonflag.WithEnv("APP")},
+		},
+		{
+			name:        "parent-flag",
+			args:        []string{`--db={"dsn":"postgres://"}`, "--token=x", "--port=1"},
+			wantMissing: nil,
+		},
+		{
+			name:        "parent-flag-without-field",
+			args:        []string{`--db={"pool":1}`, "--token=x", "--port=1"},
+			wantMissing: []string{"db.dsn"},
+			wantError:   "jsonflag: missing required values: db.dsn",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			args := test.args
+			if test.file != "" {
+				path := filepath.Join(t.TempDir(), "config.json")
+				require.NoError(t, os.WriteFile(path, []byte(test.file), 0o600))
+				args = append([]string{"--config=" + path}, args...)
+			}
+			opts := append([]jsonflag.Option{jsonflag.WithLookupEnv(func(name string) (string, bool) {
+				v, ok := test.env[name]
+				return v, ok
+			})}, test.opts...)
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+
+			_, err := jsonflag.Load(fs, &TestRequiredBase{}, args, opts...)
+			if test.wantError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.wantError)
+			requiredErr := (*jsonflag.RequiredError)(nil)
+			require.True(t, errors.As(err, &requiredErr))
+			missing := []string(nil)
+			for _, m := range requiredErr.Missing {
+				missing = append(missing, m.Flag)
+			}
+			require.Equal(t, test.wantMissing, missing)
+		})
+	}
+}
+
+func TestCheckRequiredSet(t *testing.T) {
+	t.Parallel()
+	given := &TestRequiredBase{Port: 80}
+	values := jsonflag.Recursive(given)
+	err := jsonflag.CheckRequired(values)
+	requiredErr := (*jsonflag.RequiredError)(nil)
+	require.True(t, errors.As(err, &requiredErr))
+	require.Len(t, requiredErr.Missing, 3)
+	require.Equal(t, "DSN", requiredErr.Missing[0].Path[1].Name)
+
+	require.NoError(t, values[2].Set("postgres://"))
+	require.NoError(t, values[4].Set(""))   // setting a value, even to zero, provides it
+	require.NoError(t, values[5].Set("80")) // or to its initial content
+	require.NoError(t, jsonflag.CheckRequired(values))
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Redacted is the string form of secret values (see Value.String).
+const Redacted = "***"
+
+// ErrInvalidSecret is returned in place of errors of setting secret values (or values containing secret fields), as they may contain the secret.
+var ErrInvalidSecret = errors.New("jsonflag: invalid secret value")
+
+var errNotJSONContainer = errors.New("not a JSON object or list")
+
+// secretMode tells how a secret value is printed.
+type secretMode int
+
+const (
+	notSecret         secretMode = iota
+	secretRedacted               // printed as Redacted
+	secretFingerprint            // printed as a hash fingerprint
+)
+
+// secretTag returns the secret mode set with 'secret' tag on the nearest element of path that has one. Tag value "fingerprint" makes values print as a hash fingerprint, while any boolean value turns redaction on or off (eg. for a field within a secret struct).
+func secretTag(path []reflect.StructField) secretMode {
+	for i := len(path) - 1; i >= 0; i-- {
+		tag, ok := path[i].Tag.Lookup("secret")
+		if !ok {
+			continue
+		}
+		if tag == "fingerprint" {
+			return secretFingerprint
+		}
+		if b, err := strconv.ParseBool(tag); err == nil && !b {
+			return notSecret
+		}
+		return secretRedacted
+	}
+	return notSecret
+}
+
+// redact returns the provided string form of the value (or its input) with secrets hidden. Secret values are replaced as a whole, while secret fields are replaced within JSON of values containing them (eg. structs). If the secret fields cannot be found, the whole string is replaced.
+func (val *Value) redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	if val.secret != notSecret {
+		return redactString(s, val.secret)
+	}
+	t := val.typ()
+	if !hasSecrets(t, map[reflect.Type]bool{}) {
+		return s
+	}
+	b, err := redactJSON([]byte(s), t)
+	if err != nil {
+		return Redacted
+	}
+	return string(b)
+}
+
+// redactError returns the provided error of setting the value, or ErrInvalidSecret with the value type instead, if the value is or contains a secret.
+func (val *Value) redactError(err error) error {
+	if val.secret == notSecret && !hasSecrets(val.typ(), map[reflect.Type]bool{}) {
+		return err
+	}
+	return fmt.Errorf("%w, want %s", ErrInvalidSecret, val.Type())
+}
+
+func redactString(s string, mode secretMode) string {
+	if mode == secretFingerprint {
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	}
+	return Redacted
+}
+
+// hasSecrets reports if values of the provided type contain secret struct fields.
+func hasSecrets(t reflect.Type, visited map[reflect.Type]bool) bool {
+	t = elemIfPtrType(t)
+	if visited[t] || t == timeType || isTextUnmarshaler(t) || isJSONUnmarshaler(t) {
+		return false
+	}
+	visited[t] = true
+	switch t.Kind() { //nolint:exhaustive // cases for only container types
+	case reflect.Struct:
+		for _, f := range structFields(t) {
+			if secretTag(f.path) != notSecret || hasSecrets(f.path[len(f.path)-1].Type, visited) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasSecrets(t.Elem(), visited)
+	}
+	return false
+}
+
+// redactJSON replaces values of secret struct fields in JSON of the provided type, preserving order of keys. For slices and arrays, JSON of a single element is accepted as well.
+func redactJSON(data []byte, t reflect.Type) ([]byte, error) {
+	t = elemIfPtrType(t)
+	if !hasSecrets(t, map[reflect.Type]bool{}) {
+		return data, nil
+	}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.Equal(trimmed, []byte("null")) {
+		return trimmed, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && tok != json.Delim('['):
+		return redactJSON(trimmed, t.Elem())
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return redactJSONList(dec, t)
+	case (t.Kind() == reflect.Struct || t.Kind() == reflect.Map) && tok == json.Delim('{'):
+		return redactJSONObject(dec, t)
+	}
+	return nil, errNotJSONContainer
+}
+
+func redactJSONList(dec *json.Decoder, t reflect.Type) ([]byte, error) {
+	out := []byte{'['}
+	for i := 0; dec.More(); i++ {
+		raw := json.RawMessage(nil)
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		elem, err := redactJSON(raw, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, elem...)
+	}
+	return append(out, ']'), nil
+}
+
+func redactJSONObject(dec *json.Decoder, t reflect.Type) ([]byte, error) {
+	fields := []structField(nil)
+	if t.Kind() == reflect.Struct {
+		fields = structFields(t)
+	}
+	out := []byte{'{'}
+	for i := 0; dec.More(); i++ {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(nil)
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		k, _ := key.(string)
+		elem := []byte(raw)
+		if t.Kind() == reflect.Map {
+			elem, err = redactJSON(raw, t.Elem())
+		} else if f, ok := findStructField(fields, k); ok {
+			if mode := secretTag(f.path); mode != notSecret {
+				elem, err = json.Marshal(redactString(jsonScalarString(raw), mode))
+			} else {
+				elem, err = redactJSON(raw, f.path[len(f.path)-1].Type)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			out = append(out, ',')
+		}
+		keyJSON, _ := json.Marshal(k)
+		out = append(out, keyJSON...)
+		out = append(out, ':')
+		out = append(out, elem...)
+	}
+	return append(out, '}'), nil
+}
+
+// jsonScalarString returns the string form of the provided JSON value, as printed by flag values - unquoted for strings, unchanged otherwise.
+func jsonScalarString(raw json.RawMessage) string {
+	s := ""
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
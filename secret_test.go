@@ -0,0 +1,107 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestSecretCreds struct {
+	User     string `json:"user" secret:"false"`
+	Password string `json:"password"`
+}
+
+type TestSecretBase struct {
+	Host     string          `json:"host"`
+	Password string          `json:"password" secret:"true"`
+	Token    string          `json:"token" secret:"fingerprint"`
+	Port     int             `json:"port" secret:"true"`
+	Keys     []string        `json:"keys" secret:"true"`
+	Creds    TestSecretCreds `json:"creds" secret:"true"`
+	Backends []struct {
+		URL    string `json:"url"`
+		APIKey string `json:"apiKey" secret:"true"`
+	} `json:"backends"`
+}
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	given := &TestSecretBase{Password: "default-password"}
+	values, err := jsonflag.Load(fs, given, []string{
+		"--host=example.com",
+		"--password=hunter2",
+		"--token=tok",
+		"--port=5432",
+		"--keys=k1",
+		"--keys=k2",
+		"--creds.user=admin",
+		"--creds.password=pass",
+		`--backends={"url":"a","apiKey":"key-a"}`,
+	})
+	require.NoError(t, err)
+	byName := map[string]*jsonflag.Value{}
+	for _, val := range values {
+		byName[jsonflag.JSONName(val.Path())] = val
+	}
+
+	require.Equal(t, "hunter2", given.Password)
+	require.Equal(t, "hunter2", byName["password"].Get())
+	require.Equal(t, "example.com", byName["host"].String())
+	require.Equal(t, "***", byName["password"].String())
+	require.Equal(t, "sha256:1a7674eb4ee7", byName["token"].String())
+	require.Equal(t, "***", byName["port"].String())
+	require.Equal(t, "***", byName["keys"].String())
+	require.Equal(t, "admin", byName["creds.user"].String())
+	require.Equal(t, "***", byName["creds.password"].String())
+	require.Equal(t, "***", byName["creds"].String())
+	require.Equal(t, `[{"url":"a","apiKey":"***"}]`, byName["backends"].String())
+	require.Equal(t, `{"host":"example.com","password":"***","token":"sha256:1a7674eb4ee7","port":"***","keys":"***","creds":"***","backends":[{"url":"a","apiKey":"***"}]}`, byName["input"].String())
+
+	require.Equal(t, []jsonflag.Source{
+		{Kind: jsonflag.SourceDefault, Input: "***"},
+		{Kind: jsonflag.SourceFlag, Name: "-password", Input: "***"},
+	}, byName["password"].Sources())
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFlag, Name: "-backends", Input: `{"url":"a","apiKey":"***"}`}, byName["backends"].Source())
+
+	b := &strings.Builder{}
+	require.NoError(t, jsonflag.PrintConfig(b, values, jsonflag.FormatJSON))
+	require.Equal(t, byName["input"].String()+"\n", b.String())
+	b.Reset()
+	require.NoError(t, jsonflag.PrintConfig(b, values, jsonflag.FormatArgs))
+	require.Equal(t, "--host=example.com\n'--password=***'\n--token=sha256:1a7674eb4ee7\n'--port=***'\n'--keys=***'\n'--keys=***'\n--creds.user=admin\n'--creds.password=***'\n'--backends={\"url\":\"a\",\"apiKey\":\"***\"}'\n", b.String())
+
+	b.Reset()
+	fs.SetOutput(b)
+	fs.PrintDefaults()
+	require.Contains(t, b.String(), "-password value\n    \t (default ***)")
+	require.NotContains(t, b.String(), "default-password")
+}
+
+func TestSecretSetError(t *testing.T) {
+	t.Parallel()
+	given := &TestSecretBase{}
+	_, err := jsonflag.Load(flag.NewFlagSet("", flag.ContinueOnError), given, []string{"--port=secret-port"})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), `invalid value "***" for flag -port: `))
+}
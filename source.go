@@ -0,0 +1,254 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceKind is a kind of source a value has been set from.
+type SourceKind int
+
+const (
+	SourceDefault SourceKind = iota // initial content of the value
+	SourceSet                       // direct call of Value.Set
+	SourceFile                      // config file
+	SourceEnv                       // environment variable
+	SourceFlag                      // command line flag
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceSet:
+		return "set"
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	}
+	return "SourceKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Source describes where a value has been set from.
+type Source struct {
+	Kind  SourceKind
+	Name  string // flag name, environment variable name or config file name with line number, if known
+	Input string // raw input the value has been set from (string form of the value for defaults and config files)
+}
+
+func (s Source) String() string {
+	if s.Name == "" {
+		return s.Kind.String()
+	}
+	return s.Kind.String() + " " + s.Name
+}
+
+// Source returns the source of the last successful set of the value.
+func (val *Value) Source() Source {
+	if !val.isInitialized() {
+		return Source{}
+	}
+	if len(val.sources) == 0 {
+		return Source{Kind: SourceDefault, Input: val.String()}
+	}
+	return val.sources[len(val.sources)-1]
+}
+
+// Sources returns the initial content of the value followed by sources of all successful sets of the value, in order.
+func (val *Value) Sources() []Source {
+	if !val.isInitialized() {
+		return nil
+	}
+	if len(val.sources) == 0 {
+		return []Source{{Kind: SourceDefault, Input: val.String()}}
+	}
+	return slices.Clone(val.sources)
+}
+
+// recordDefault records the current content of the value as its default, unless the value has been already set.
+func (val *Value) recordDefault() {
+	if len(val.sources) == 0 {
+		val.sources = append(val.sources, Source{Kind: SourceDefault, Input: val.String()})
+	}
+}
+
+// record records the provided source of the value set without calling Set. The default has to be recorded beforehand.
+func (val *Value) record(src Source) {
+	val.sources = append(val.sources, src)
+}
+
+// Explain writes a report listing, for every flag value, all sources the value has been set from, marking the one that won. Names are created as in Register (see WithName and WithCase).
+func Explain(w io.Writer, values []*Value, opts ...Option) error {
+	o := newOptions(opts)
+	for _, val := range values {
+		if _, err := fmt.Fprintln(w, o.name(val)); err != nil {
+			return err
+		}
+		sources := val.Sources()
+		for i, src := range sources {
+			mark := " "
+			if i == len(sources)-1 {
+				mark = "*"
+			}
+			if _, err := fmt.Fprintf(w, "  %s %s: %q\n", mark, src, src.Input); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// linkChildren links each of the provided flag values with values within it and values containing it, so that their sources are recorded on its sets. Previous links are replaced.
+func linkChildren(values []*Value) {
+	for _, val := range values {
+		val.children, val.parents = nil, nil
+	}
+	for _, val := range values {
+		for _, other := range values {
+			if contains(val, other) {
+				val.children = append(val.children, other)
+				other.parents = append(other.parents, val)
+			}
+		}
+	}
+}
+
+// recordFile records the provided config file as the source of all values with JSON names present in the file.
+func recordFile(values []*Value, path string, data []byte) {
+	lines := configLines(data)
+	for _, val := range values {
+		if len(val.Path()) == 0 { // the base value has no key
+			continue
+		}
+		name := JSONName(val.Path())
+		line, ok := lines[name]
+		if !ok {
+			for k, l := range lines { // keys are matched case-insensitively, as package "encoding/json" does
+				if strings.EqualFold(k, name) {
+					line, ok = l, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		fileName := path
+		if line > 0 {
+			fileName += ":" + strconv.Itoa(line)
+		}
+		val.record(Source{Kind: SourceFile, Name: fileName, Input: val.String()})
+	}
+}
+
+// configLines returns line numbers of all keys and list elements in the provided config file, by path joined with '.'. Config files being JSON (with comments) and YAML are supported. Line numbers are zero if unknown.
+func configLines(data []byte) map[string]int {
+	lines := map[string]int{}
+	if std, err := (JSONC{UnquotedKeys: true}).Standardize(data); err == nil { // standardization preserves line numbers
+		if err := collectJSONLines(json.NewDecoder(bytes.NewReader(std)), std, nil, lines); err == nil {
+			return lines
+		}
+	}
+	clear(lines)
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal(data, &doc); err == nil {
+		collectYAMLLines(&doc, nil, lines)
+	}
+	return lines
+}
+
+func collectJSONLines(dec *json.Decoder, data []byte, path []string, lines map[string]int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			p := append(slices.Clone(path), fmt.Sprint(key))
+			lines[strings.Join(p, ".")] = lineAt(data, int(dec.InputOffset()))
+			if err := collectJSONLines(dec, data, p, lines); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			p := append(slices.Clone(path), strconv.Itoa(i))
+			lines[strings.Join(p, ".")] = lineAt(data, skipSpaceAndComments(data, skipComma(data, int(dec.InputOffset()))))
+			if err := collectJSONLines(dec, data, p, lines); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+	return err
+}
+
+func collectYAMLLines(n *yaml.Node, path []string, lines map[string]int) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			collectYAMLLines(c, path, lines)
+		}
+	case yaml.AliasNode:
+		collectYAMLLines(n.Alias, path, lines)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.ShortTag() == "!!merge" {
+				continue
+			}
+			p := append(slices.Clone(path), k.Value)
+			lines[strings.Join(p, ".")] = k.Line
+			collectYAMLLines(v, p, lines)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			p := append(slices.Clone(path), strconv.Itoa(i))
+			lines[strings.Join(p, ".")] = c.Line
+			collectYAMLLines(c, p, lines)
+		}
+	}
+}
+
+// lineAt returns line number of the provided offset, starting from 1.
+func lineAt(data []byte, offset int) int {
+	return bytes.Count(data[:min(offset, len(data))], []byte{'\n'}) + 1
+}
+
+// skipComma returns offset just after the comma following the provided offset (skipping white spaces), if any.
+func skipComma(data []byte, offset int) int {
+	if i := skipSpaceAndComments(data, offset); i < len(data) && data[i] == ',' {
+		return i + 1
+	}
+	return offset
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		file    string
+		decoder func([]byte, any) error
+	}{
+		{
+			name: "json",
+			file: "{\n  \"host\": \"file\",\n  // comment\n  \"servers\": [\n    {\"Value\": \"a\"},\n    {\"Value\": \"b\"}\n  ],\n  \"port\": 8080,\n}",
+		},
+		{
+			name:    "yaml",
+			file:    "# config\nhost: file\n# comment\nservers:\n  - Value: a\n  - Value: b\n\nport: 8080\n",
+			decoder: jsonflag.DecodeYAML,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(path, []byte(test.file), 0o600))
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			opts := []jsonflag.Option{
+				jsonflag.WithEnv("APP"),
+				jsonflag.WithLookupEnv(func(name string) (string, bool) { return map[string]string{"APP_PORT": "1"}[name], name == "APP_PORT" }),
+				jsonflag.WithJSONDecoder(jsonflag.JSONC{}.Decode),
+			}
+			if test.decoder != nil {
+				opts = append(opts, jsonflag.WithJSONDecoder(test.decoder))
+			}
+			given := &TestLoadBase{Host: "localhost", Port: 80}
+
+			values, err := jsonflag.Load(fs, given, []string{"--config", path, "--port=9090", "--tags=a", "--tags=b"}, opts...)
+			require.NoError(t, err)
+			byName := map[string]*jsonflag.Value{}
+			for _, val := range values {
+				byName[jsonflag.JSONName(val.Path())] = val
+			}
+
+			require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFile, Name: path + ":2", Input: "file"}, byName["host"].Source())
+			require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFile, Name: path + ":4", Input: `[{"Value":"a"},{"Value":"b"}]`}, byName["servers"].Source())
+			require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFlag, Name: "-port", Input: "9090"}, byName["port"].Source())
+			require.Equal(t, []jsonflag.Source{
+				{Kind: jsonflag.SourceDefault, Input: "80"},
+				{Kind: jsonflag.SourceFile, Name: path + ":8", Input: "8080"},
+				{Kind: jsonflag.SourceEnv, Name: "APP_PORT", Input: "1"},
+				{Kind: jsonflag.SourceFlag, Name: "-port", Input: "9090"},
+			}, byName["port"].Sources())
+			require.Equal(t, []jsonflag.Source{
+				{Kind: jsonflag.SourceDefault},
+				{Kind: jsonflag.SourceFlag, Name: "-tags", Input: "a"},
+				{Kind: jsonflag.SourceFlag, Name: "-tags", Input: "b"},
+			}, byName["tags"].Sources())
+			require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceDefault}, byName["limits"].Source())
+
+			b := &strings.Builder{}
+			require.NoError(t, jsonflag.Explain(b, []*jsonflag.Value{byName["host"], byName["port"]}))
+			want := "host\n" +
+				"    default: \"localhost\"\n" +
+				"  * file " + path + ":2: \"file\"\n" +
+				"port\n" +
+				"    default: \"80\"\n" +
+				"    file " + path + ":8: \"8080\"\n" +
+				"    env APP_PORT: \"1\"\n" +
+				"  * flag -port: \"9090\"\n"
+			require.Equal(t, want, b.String())
+		})
+	}
+}
+
+func TestSourceRegister(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	given := &TestRegisterBase{}
+	values, err := jsonflag.Register(fs, given)
+	require.NoError(t, err)
+	require.NoError(t, fs.Parse([]string{"--fooBar=foo"}))
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFlag, Name: "-fooBar", Input: "foo"}, values[1].Source())
+
+	pfs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	values, err = jsonflag.RegisterPFlags(pfs, &TestRegisterBase{})
+	require.NoError(t, err)
+	require.NoError(t, pfs.Parse([]string{"--fooBar=foo"}))
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFlag, Name: "--fooBar", Input: "foo"}, values[1].Source())
+
+	val := jsonflag.New(&given.FooBar)
+	require.NoError(t, val.Set("bar"))
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceSet, Input: "bar"}, val.Source())
+	require.Equal(t, "set", val.Source().String())
+}
+
+func TestSourceRegisterParent(t *testing.T) {
+	t.Parallel()
+	wantSources := []jsonflag.Source{
+		{Kind: jsonflag.SourceDefault, Input: "default"},
+		{Kind: jsonflag.SourceFlag, Name: "-input", Input: "x"},
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	values, err := jsonflag.Register(fs, &TestRegisterBase{FooBar: "default"})
+	require.NoError(t, err)
+	require.NoError(t, fs.Parse([]string{`--input={"fooBar":"x"}`}))
+	require.Equal(t, wantSources, values[1].Sources())
+	require.Equal(t, []jsonflag.Source{{Kind: jsonflag.SourceDefault}}, values[3].Sources())
+
+	b := &strings.Builder{}
+	require.NoError(t, jsonflag.Explain(b, values[1:2]))
+	require.Equal(t, "fooBar\n    default: \"default\"\n  * flag -input: \"x\"\n", b.String())
+
+	require.NoError(t, fs.Parse([]string{"--nested.bazQux=2"}))
+	require.Equal(t, []jsonflag.Source{
+		{Kind: jsonflag.SourceDefault, Input: `{"fooBar":"default","nested":{"bazQux":0}}`},
+		{Kind: jsonflag.SourceFlag, Name: "-input", Input: `{"fooBar":"x"}`},
+		{Kind: jsonflag.SourceFlag, Name: "-nested.bazQux", Input: `{"fooBar":"x","nested":{"bazQux":2}}`},
+	}, values[0].Sources())
+	require.Equal(t, []jsonflag.Source{
+		{Kind: jsonflag.SourceDefault, Input: `{"bazQux":0}`},
+		{Kind: jsonflag.SourceFlag, Name: "-nested.bazQux", Input: `{"bazQux":2}`},
+	}, values[2].Sources())
+
+	b.Reset()
+	require.NoError(t, jsonflag.Explain(b, values[2:3]))
+	require.Equal(t, "nested\n    default: \"{\\\"bazQux\\\":0}\"\n  * flag -nested.bazQux: \"{\\\"bazQux\\\":2}\"\n", b.String())
+
+	pfs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	values, err = jsonflag.RegisterPFlags(pfs, &TestRegisterBase{FooBar: "default"})
+	require.NoError(t, err)
+	require.NoError(t, pfs.Parse([]string{`--input={"fooBar":"x"}`}))
+	wantSources[1].Name = "--input"
+	require.Equal(t, wantSources, values[1].Sources())
+}
+
+func TestSourceLoadElem(t *testing.T) {
+	t.Parallel()
+	given := &TestLoadElemsBase{}
+	values, err := jsonflag.Load(flag.NewFlagSet("", flag.ContinueOnError), given, []string{"--regions.us.port=1"})
+	require.NoError(t, err)
+	require.Equal(t, "regions", jsonflag.JSONName(values[2].Path()))
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFlag, Name: "-regions.us.port", Input: `{"us":{"host":"","port":1,"maxConns":0}}`}, values[2].Source())
+	require.Equal(t, jsonflag.Source{Kind: jsonflag.SourceFlag, Name: "-regions.us.port", Input: "1"}, values[len(values)-1].Source())
+}
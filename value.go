@@ -17,10 +17,24 @@ package jsonflag
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrArrayLength is returned when setting an array flag value with invalid number of elements.
+	ErrArrayLength = errors.New("jsonflag: invalid number of array elements")
+	// ErrUnknownPath is returned when looking up a flag value under a path that does not exist.
+	ErrUnknownPath = errors.New("jsonflag: unknown path")
+	// ErrMapEntry is returned when setting a map flag value with an entry not in key=value form.
+	ErrMapEntry = errors.New("jsonflag: invalid map entry, want key=value")
 )
 
 // New returns new flag value for the provided value. It returns nil if the value cannot be used as flag value.
@@ -32,7 +46,7 @@ func New(base any) *Value {
 	if !v.CanSet() && (v.Kind() != reflect.Pointer || v.IsNil()) {
 		return nil
 	}
-	return newValue(v, nil, nil)
+	return newValue(v, nil, nil, nil)
 }
 
 // FilterFunc is a function that can be used to decide if a flag value should be included in recursive results as well as if flag values finding should descend into the sub-values.
@@ -48,6 +62,11 @@ const (
 	SkipNoDescend     FilterResult = 0b11 // indicates that the given flag value should be skipped and values finding should NOT descend into sub-values
 	noDescendMask     FilterResult = 0b01
 	skipMask          FilterResult = 0b10
+
+	descendJSONUnmarshalerMask FilterResult = 0b100
+	includeJSONIgnoredMask     FilterResult = 0b1000
+	expandMapsMask             FilterResult = 0b10000
+	expandSlicesMask           FilterResult = 0b100000
 )
 
 // Filter applies all filter function to the provided flag value and returns filtering decision.
@@ -61,7 +80,39 @@ func Filter(val *Value, filters ...FilterFunc) (r FilterResult) {
 	return r
 }
 
+// DescendJSONUnmarshalers is a filter function that makes values finding descend into fields of struct types implementing json.Unmarshaler. By default such values are treated as opaque JSON values, set only as a whole through the type methods.
+func DescendJSONUnmarshalers(*Value) FilterResult {
+	return IncludeAndDescend | descendJSONUnmarshalerMask
+}
+
+// IncludeJSONIgnored is a filter function that makes values finding include fields tagged with `json:"-"`. By default such fields are skipped (together with all the values within), as they cannot be round-tripped through JSON.
+func IncludeJSONIgnored(*Value) FilterResult {
+	return IncludeAndDescend | includeJSONIgnoredMask
+}
+
+// ExpandMaps is a filter function that makes values finding descend into elements already present in maps. Each map element is returned as a separate flag value (as well as all values within), with path ending with a map key (see Load for flags of elements not present in maps and Value.Lookup for setting them directly).
+func ExpandMaps(*Value) FilterResult {
+	return IncludeAndDescend | expandMapsMask
+}
+
+// ExpandSlices is a filter function that makes values finding descend into elements already present in slices. Each slice element is returned as a separate flag value (as well as all values within), with path ending with a slice index (see Load for flags of elements past the end of slices and Value.Lookup for setting them directly). Elements are modified in place.
+func ExpandSlices(*Value) FilterResult {
+	return IncludeAndDescend | expandSlicesMask
+}
+
+// MaxDepth returns a filter function that prevents values finding from descending deeper than the provided number of struct fields along the path. Values at the maximal depth are still included, as a single flag value each.
+func MaxDepth(depth int) FilterFunc {
+	return func(val *Value) FilterResult {
+		if len(val.Path()) >= depth {
+			return IncludeNoDescend
+		}
+		return IncludeAndDescend
+	}
+}
+
 // Recursive returns set of flag values for the provided value and all values within, recursively, according to the provided filters. Function silently skips all the values that cannot be used as flag values.
+//
+// Self-referential types are not expanded infinitely - a struct field, which type is already being expanded along the path, is returned as a single flag value and values finding does not descend into it. Similarly, values finding does not descend into types implementing json.Unmarshaler (see DescendJSONUnmarshalers).
 func Recursive(base any, filters ...FilterFunc) []*Value {
 	if base == nil {
 		return nil
@@ -70,13 +121,16 @@ func Recursive(base any, filters ...FilterFunc) []*Value {
 	if !v.CanSet() && (v.Kind() != reflect.Pointer || v.IsNil()) {
 		return nil
 	}
-	return recursive(v, nil, nil, filters)
+	return recursive(v, nil, nil, nil, nil, filters)
 }
 
-func recursive(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField, filters []FilterFunc) []*Value {
+func recursive(base reflect.Value, entry *elemEntry, fieldsIndexes []int, fields []reflect.StructField, visited []reflect.Type, filters []FilterFunc) []*Value {
 	values := []*Value(nil)
-	val := newValue(base, slices.Clone(fieldsIndexes), slices.Clone(fields))
+	val := newValue(base, entry, slices.Clone(fieldsIndexes), slices.Clone(fields))
 	filterResult := Filter(val, filters...)
+	if len(fields) > 0 && isJSONIgnored(fields[len(fields)-1]) && filterResult&includeJSONIgnoredMask == 0 {
+		return nil
+	}
 	if filterResult&skipMask == 0 {
 		values = append(values, val)
 	}
@@ -91,20 +145,68 @@ func recursive(base reflect.Value, fieldsIndexes []int, fields []reflect.StructF
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
-	if t.Kind() != reflect.Struct {
+	if t.Kind() == reflect.Map && filterResult&expandMapsMask != 0 && !isTextUnmarshaler(t) && !isJSONUnmarshaler(t) {
+		return append(values, recursiveMap(val, visited, filters)...)
+	}
+	if t.Kind() == reflect.Slice && filterResult&expandSlicesMask != 0 && val.isSlice {
+		return append(values, recursiveSlice(val, visited, filters)...)
+	}
+	if t.Kind() != reflect.Struct || isTextUnmarshaler(t) || slices.Contains(visited, t) {
+		return values
+	}
+	if isJSONUnmarshaler(t) && filterResult&descendJSONUnmarshalerMask == 0 {
 		return values
 	}
-	for i := range t.NumField() {
-		field := t.Field(i)
-		if !field.IsExported() {
-			continue
+	visited = append(visited, t)
+	for _, field := range structFields(t) {
+		values = append(values, recursive(base, entry, append(fieldsIndexes, field.index...), append(fields, field.path...), visited, filters)...)
+	}
+	return values
+}
+
+func recursiveMap(m *Value, visited []reflect.Type, filters []FilterFunc) []*Value {
+	m.load()
+	mv := elemIfPtr(m.get())
+	keys := map[string]reflect.Value{}
+	for _, k := range mv.MapKeys() {
+		if name, ok := formatMapKey(k); ok {
+			keys[name] = k
 		}
-		values = append(values, recursive(base, append(fieldsIndexes, i), append(fields, field), filters)...)
+	}
+	values := []*Value(nil)
+	for _, name := range slices.Sorted(maps.Keys(keys)) {
+		e := newMapEntry(m, keys[name])
+		values = append(values, recursive(e.elem, e, nil, append(slices.Clone(m.fields), elemField(name, mv.Type().Elem())), visited, filters)...)
 	}
 	return values
 }
 
-func newValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+func recursiveSlice(s *Value, visited []reflect.Type, filters []FilterFunc) []*Value {
+	s.load()
+	sv := elemIfPtr(s.get())
+	values := []*Value(nil)
+	for i := range sv.Len() {
+		e := newSliceEntry(s, i)
+		values = append(values, recursive(e.elem, e, nil, append(slices.Clone(s.fields), elemField(strconv.Itoa(i), sv.Type().Elem())), visited, filters)...)
+	}
+	return values
+}
+
+func newValue(base reflect.Value, entry *elemEntry, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	val := newKindValue(base, fieldsIndexes, fields)
+	if val == nil {
+		return nil
+	}
+	val.entry = entry
+	val.separator = separatorTag(fields)
+	val.secret = secretTag(fields)
+	if len(fields) > 0 && hasJSONOption(fields[len(fields)-1], "string") {
+		quoteValue(val)
+	}
+	return val
+}
+
+func newKindValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
 	t := base.Type()
 	if len(fields) > 0 {
 		t = fields[len(fields)-1].Type
@@ -112,6 +214,18 @@ func newValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructFi
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
+	switch t {
+	case durationType:
+		return newDurationValue(base, fieldsIndexes, fields)
+	case timeType:
+		return newTimeValue(base, fieldsIndexes, fields)
+	}
+	if isTextUnmarshaler(t) {
+		return newTextValue(base, fieldsIndexes, fields)
+	}
+	if isJSONUnmarshaler(t) {
+		return newJSONValue(base, fieldsIndexes, fields)
+	}
 	switch t.Kind() { //nolint:exhaustive // cases for only supported types
 	case reflect.Bool:
 		return newBoolValue(base, fieldsIndexes, fields)
@@ -147,19 +261,55 @@ func newValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructFi
 		return newStringValue(base, fieldsIndexes, fields)
 	case reflect.Slice:
 		return newSliceValue(t, base, fieldsIndexes, fields)
+	case reflect.Array:
+		return newArrayValue(t, base, fieldsIndexes, fields)
 	case reflect.Map:
 		return newMapValue(base, fieldsIndexes, fields)
 	case reflect.Struct:
 		return newStructValue(base, fieldsIndexes, fields)
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return newInterfaceValue(base, fieldsIndexes, fields)
+		}
 	}
 	return nil
 }
 
 func newSliceValue(typ reflect.Type, base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	val := newSliceKindValue(typ, base, fieldsIndexes, fields)
+	if val != nil && typ.Elem().Kind() != reflect.Uint8 { // slice of bytes is always set as a whole
+		val.isSlice = true
+		val.appendOnly, _ = strconv.ParseBool(lastTag(fields, "append"))
+	}
+	return val
+}
+
+func separatorTag(fields []reflect.StructField) rune {
+	sep := lastTag(fields, "sep")
+	if utf8.RuneCountInString(sep) != 1 {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(sep)
+	return r
+}
+
+func newSliceKindValue(typ reflect.Type, base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
 	t := typ.Elem()
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
+	switch t {
+	case durationType:
+		return newDurationSliceValue(base, fieldsIndexes, fields)
+	case timeType:
+		return newTimeSliceValue(base, fieldsIndexes, fields)
+	}
+	if isTextUnmarshaler(t) {
+		return newTextSliceValue(base, fieldsIndexes, fields)
+	}
+	if isJSONUnmarshaler(t) {
+		return newJSONSliceValue(base, fieldsIndexes, fields)
+	}
 	switch t.Kind() { //nolint:exhaustive // cases for only supported types
 	case reflect.Bool:
 		return newBoolSliceValue(base, fieldsIndexes, fields)
@@ -205,6 +355,10 @@ func newSliceValue(typ reflect.Type, base reflect.Value, fieldsIndexes []int, fi
 		return newMapSliceValue(base, fieldsIndexes, fields)
 	case reflect.Struct:
 		return newStructSliceValue(base, fieldsIndexes, fields)
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return newInterfaceSliceValue(base, fieldsIndexes, fields)
+		}
 	}
 	return nil
 }
@@ -218,9 +372,22 @@ type Value struct {
 	encodeFn      func(any) ([]byte, error)
 	setFn         func(*Value, string) error
 	decodeFn      func([]byte, any) error
+	jsonDecodeFn  func([]byte, any) error
+	resolveFn     func(string) (string, error)
 	isBool        bool
-}
-
+	isSlice       bool       // whether elements are appended on set
+	appendOnly    bool       // whether elements are appended to the initial slice instead of replacing it
+	separator     rune       // separator of multiple elements set at once (zero for default)
+	secret        secretMode // how the value is printed, if secret
+	sources       []Source   // initial content of the value followed by sources of all successful sets
+	flagName      string     // name of the flag (with dashes) the value is registered under (if any)
+	index         int        // index of the next element to set for array values
+	entry         *elemEntry // holder of the map or slice element the value is stored in (if any)
+	children      []*Value   // flag values within the value, which sources are recorded on sets of the value
+	parents       []*Value   // flag values the value is within, which sources are recorded on sets of the value
+}
+
+// Path returns path of struct fields leading to the value. Map keys and slice indexes along the path are represented by elements with the key or index as name and nil Index.
 func (val *Value) Path() []reflect.StructField {
 	if !val.isInitialized() {
 		return nil
@@ -239,13 +406,21 @@ func (val *Value) Get() any {
 	if !val.isInitialized() {
 		return nil
 	}
+	val.load()
 	return val.get().Interface()
 }
 
+// String returns string form of the value. Secret values (see 'secret' tag) are redacted, as well as secret fields within JSON of the value.
 func (val *Value) String() string {
 	if !val.isInitialized() {
 		return ""
 	}
+	return val.redact(val.plainString())
+}
+
+// plainString returns string form of the value, without redacting secrets.
+func (val *Value) plainString() string {
+	val.load()
 	if val.encodeFn != nil {
 		b, err := val.encodeFn(val.get().Interface())
 		if err != nil {
@@ -260,12 +435,159 @@ func (val *Value) Set(to string) error {
 	if !val.isInitialized() {
 		return nil
 	}
+	if val.flagName != "" {
+		return val.setFrom(Source{Kind: SourceFlag, Name: val.flagName, Input: to}, to)
+	}
+	return val.setFrom(Source{Kind: SourceSet, Input: to}, to)
+}
+
+// setFrom sets the value, recording the provided source on success.
+func (val *Value) setFrom(src Source, to string) error {
+	if !val.isInitialized() {
+		return nil
+	}
+	if val.resolveFn != nil {
+		resolved, err := val.resolveFn(to)
+		if err != nil {
+			return err
+		}
+		to = resolved
+	}
+	val.load()
+	val.recordDefault()
+	related := slices.Concat(val.children, val.parents)
+	before := make([]string, len(related))
+	for i, other := range related {
+		other.recordDefault()
+		before[i] = other.plainString()
+	}
+	last := val.sources[len(val.sources)-1]
+	if err := val.set(to, last.Kind != src.Kind || last.Name != src.Name); err != nil {
+		return err
+	}
+	val.store()
+	src.Input = val.redact(src.Input)
+	val.sources = append(val.sources, src)
+	for i, other := range related { // values within and containing the value changed by the set have the same source, with their new content as input
+		if s := other.plainString(); s != before[i] {
+			other.record(Source{Kind: src.Kind, Name: src.Name, Input: other.redact(s)})
+		}
+	}
+	return nil
+}
+
+// set sets the value. For slices, the first set from the given source replaces the previous elements (see setSlice). For arrays, the first set from the given source starts again from the first element.
+func (val *Value) set(to string, first bool) error {
+	if first {
+		val.index = 0
+	}
 	if val.decodeFn != nil {
 		return val.decodeFn([]byte(to), elemIfPtr(val.get()).Addr().Interface())
 	}
+	if val.isSlice {
+		return val.setSlice(to, first)
+	}
 	return val.setFn(val, to)
 }
 
+// setSlice appends elements to the slice. On the first set, unless in append only mode, it starts from an empty slice, so that the previous elements are replaced. The previous slice is restored on error.
+func (val *Value) setSlice(to string, first bool) error {
+	target := val.get()
+	if target.Kind() == reflect.Pointer && !target.CanSet() {
+		target = target.Elem()
+	}
+	prev := reflect.New(target.Type()).Elem()
+	prev.Set(target)
+	if !val.appendOnly && first {
+		target.SetZero()
+	}
+	if err := val.appendElems(to); err != nil {
+		target.Set(prev)
+		return err
+	}
+	return nil
+}
+
+// appendElems appends to the slice a single element or, if separator is set, elements of a JSON list (when starting with '[') or a separated list.
+func (val *Value) appendElems(to string) error {
+	if val.separator == 0 {
+		return val.setFn(val, to)
+	}
+	elems, err := []string(nil), error(nil)
+	if strings.HasPrefix(strings.TrimSpace(to), "[") {
+		raw := elemIfPtrType(elemIfPtrType(val.typ()).Elem()).Kind() == reflect.Interface
+		elems, err = splitJSONList(to, raw, val.unmarshalJSON)
+	} else {
+		elems, err = splitList(to, val.separator)
+	}
+	if err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if err := val.setFn(val, elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns flag value for the value under the provided path, relative to the value. Path elements are JSON names of struct fields, map keys or slice indexes. Map keys do not need to be present in the map and slice indexes may be past the end of the slice - setting the returned flag value adds the element (growing the slice with zero elements, if needed).
+func (val *Value) Lookup(path ...string) (*Value, error) {
+	if !val.isInitialized() {
+		return nil, ErrUnknownPath
+	}
+	cur := val
+	for i, name := range path {
+		next, err := cur.lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, strings.Join(path[:i+1], "."))
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+// SetPath sets the value under the provided path, relative to the value (see Lookup).
+func (val *Value) SetPath(path []string, to string) error {
+	sub, err := val.Lookup(path...)
+	if err != nil {
+		return err
+	}
+	return sub.Set(to)
+}
+
+func (val *Value) lookup(name string) (*Value, error) {
+	t := elemIfPtrType(val.typ())
+	sub := (*Value)(nil)
+	switch {
+	case t.Kind() == reflect.Map:
+		key, err := parseMapKey(t.Key(), name)
+		if err != nil {
+			return nil, err
+		}
+		e := newMapEntry(val, key)
+		sub = newValue(e.elem, e, nil, append(slices.Clone(val.fields), elemField(name, t.Elem())))
+	case t.Kind() == reflect.Slice && val.isSlice:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("%w: invalid slice index", ErrUnknownPath)
+		}
+		e := newSliceEntry(val, i)
+		sub = newValue(e.elem, e, nil, append(slices.Clone(val.fields), elemField(strconv.Itoa(i), t.Elem())))
+	case t.Kind() == reflect.Struct && !isTextUnmarshaler(t):
+		for _, f := range structFields(t) {
+			if f.name == name {
+				sub = newValue(val.base, val.entry, append(slices.Clone(val.fieldsIndexes), f.index...), append(slices.Clone(val.fields), f.path...))
+				break
+			}
+		}
+	}
+	if sub == nil {
+		return nil, ErrUnknownPath
+	}
+	return sub, nil
+}
+
 func (val *Value) SetEncoder(fn func(any) ([]byte, error)) {
 	if !val.isInitialized() {
 		return
@@ -280,6 +602,38 @@ func (val *Value) SetDecoder(fn func([]byte, any) error) {
 	val.decodeFn = fn
 }
 
+// SetResolver sets the function used to resolve references in the input before it is parsed, eg. reading "@path" from a file (see WithReferences). Inputs that are not references should be returned unchanged. Sources of the value record the unresolved input. By default references are not resolved.
+func (val *Value) SetResolver(fn func(string) (string, error)) {
+	if !val.isInitialized() {
+		return
+	}
+	val.resolveFn = fn
+}
+
+// SetJSONDecoder sets the function used to decode JSON objects and lists provided as input, eg. JSONC.Decode. Unlike decoder set with SetDecoder, it does not replace the input handling of the value, like appending slice elements or setting key=value map entries. By default package "encoding/json" is used.
+func (val *Value) SetJSONDecoder(fn func([]byte, any) error) {
+	if !val.isInitialized() {
+		return
+	}
+	val.jsonDecodeFn = fn
+}
+
+// SetAppend sets whether slice elements are appended to the initial slice. By default the first set from each source (like a flag or an environment variable, see Source) replaces the previous slice and the following ones append to it, unless the field is tagged with 'append' tag set to true.
+func (val *Value) SetAppend(appendOnly bool) {
+	if !val.isInitialized() {
+		return
+	}
+	val.appendOnly = appendOnly
+}
+
+// SetSeparator sets separator of multiple slice elements set at once, eg. `80,443` for ',' separator. Elements starting with '"' are quoted as in package "encoding/csv" and separators within JSON objects and lists do not split elements. Lists starting with '[' are parsed as JSON lists instead. Zero separator, which is the default unless the field is tagged with 'sep' tag, allows setting only a single element at once. For maps it sets separator of key=value entries (',' by default).
+func (val *Value) SetSeparator(sep rune) {
+	if !val.isInitialized() {
+		return
+	}
+	val.separator = sep
+}
+
 func (val *Value) IsBoolFlag() bool {
 	if !val.isInitialized() {
 		return false
@@ -287,6 +641,122 @@ func (val *Value) IsBoolFlag() bool {
 	return val.isBool
 }
 
+// layout returns time layout used by the value, set with 'layout' tag. By default RFC 3339 is used.
+func (val *Value) layout() string {
+	if l := lastTag(val.fields, "layout"); l != "" {
+		return l
+	}
+	return time.RFC3339Nano
+}
+
+// quoteValue makes the provided boolean or numeric value accept also values quoted as JSON strings, as package "encoding/json" does for fields with `json:",string"` tag option. String form of the value stays unquoted. Values of other types are left unchanged.
+func quoteValue(val *Value) {
+	t := elemIfPtrType(val.typ())
+	if t == durationType || isTextUnmarshaler(t) || isJSONUnmarshaler(t) {
+		return
+	}
+	switch t.Kind() { //nolint:exhaustive // cases for only quotable types
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return
+	}
+	setFn := val.setFn
+	val.setFn = func(val *Value, to string) error {
+		if strings.HasPrefix(to, `"`) {
+			if err := json.Unmarshal([]byte(to), &to); err != nil {
+				return err
+			}
+		}
+		return setFn(val, to)
+	}
+}
+
+// unmarshalJSON decodes JSON input with the JSON decoder of the value (if any) or package "encoding/json" otherwise.
+func (val *Value) unmarshalJSON(to string, v any) error {
+	if val.jsonDecodeFn != nil {
+		return val.jsonDecodeFn([]byte(to), v)
+	}
+	return unmarshalJSON([]byte(to), v)
+}
+
+// load refreshes the value from the map or slice holding it (if any).
+func (val *Value) load() {
+	if val.entry != nil {
+		val.entry.load()
+	}
+}
+
+// store saves the value in the map or slice holding it (if any).
+func (val *Value) store() {
+	if val.entry != nil {
+		val.entry.store()
+	}
+}
+
+// elemEntry holds a copy of a map or slice element. Map elements are not addressable and slice elements may not exist yet, so they have to be copied out of the container to be modified and stored back afterwards.
+type elemEntry struct {
+	container *Value        // flag value of the map or slice
+	key       reflect.Value // map key (invalid for slice elements)
+	index     int           // slice index
+	elem      reflect.Value // addressable copy of the element
+}
+
+func newMapEntry(m *Value, key reflect.Value) *elemEntry {
+	return &elemEntry{
+		container: m,
+		key:       key,
+		elem:      reflect.New(elemIfPtrType(m.typ()).Elem()).Elem(),
+	}
+}
+
+func newSliceEntry(s *Value, index int) *elemEntry {
+	return &elemEntry{
+		container: s,
+		index:     index,
+		elem:      reflect.New(elemIfPtrType(s.typ()).Elem()).Elem(),
+	}
+}
+
+func (e *elemEntry) load() {
+	e.container.load()
+	c := elemIfPtr(e.container.get())
+	if e.key.IsValid() {
+		if x := c.MapIndex(e.key); x.IsValid() {
+			e.elem.Set(x)
+			return
+		}
+	} else if e.index < c.Len() {
+		e.elem.Set(c.Index(e.index))
+		return
+	}
+	e.elem.SetZero()
+}
+
+func (e *elemEntry) store() {
+	c := e.container.get()
+	t := elemIfPtrType(e.container.typ())
+	if e.key.IsValid() {
+		if elemIfPtr(c).IsNil() {
+			reflectValueSet(c, reflect.MakeMap(t))
+		}
+		elemIfPtr(c).SetMapIndex(e.key, e.elem)
+	} else {
+		if n := e.index + 1 - elemIfPtr(c).Len(); n > 0 {
+			reflectValueSet(c, addressable(reflect.AppendSlice(elemIfPtr(c), reflect.MakeSlice(t, n, n))))
+		}
+		elemIfPtr(c).Index(e.index).Set(e.elem)
+	}
+	e.container.store()
+}
+
+// elemField returns path element representing a map key or a slice index. Unlike path elements representing struct fields, it has nil Index.
+func elemField(name string, t reflect.Type) reflect.StructField {
+	return reflect.StructField{Name: name, Type: t}
+}
+
 func (val *Value) isInitialized() bool {
 	return val != nil && val.base.IsValid()
 }
@@ -382,6 +852,7 @@ func newBoolValue(base reflect.Value, fieldsIndexes []int, fields []reflect.Stru
 		typeName:      "bool",
 		stringFn:      boolValueString,
 		setFn:         boolValueSet,
+		isBool:        true,
 	}
 }
 
@@ -779,6 +1250,123 @@ func bytesValueSet(val *Value, to string) error {
 	return nil
 }
 
+func newDurationValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "duration",
+		stringFn:      durationValueString,
+		setFn:         durationValueSet,
+	}
+}
+
+func durationValueString(val *Value) string {
+	v := time.Duration(elemIfPtr(val.get()).Int())
+	if v == 0 {
+		return ""
+	}
+	return v.String()
+}
+
+func durationValueSet(val *Value, to string) error {
+	v, err := parseDuration(to)
+	if err != nil {
+		return err
+	}
+	reflectValueSet(val.get(), reflect.ValueOf(v))
+	return nil
+}
+
+func newTimeValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "time",
+		stringFn:      timeValueString,
+		setFn:         timeValueSet,
+	}
+}
+
+func timeValueString(val *Value) string {
+	v := elemIfPtr(val.get()).Interface().(time.Time) //nolint:forcetypeassert // no nee to check type conversion result - correct value should be selected by the newValue function
+	if v.IsZero() {
+		return ""
+	}
+	return v.Format(val.layout())
+}
+
+func timeValueSet(val *Value, to string) error {
+	v, err := parseTime(val.layout(), to)
+	if err != nil {
+		return err
+	}
+	reflectValueSet(val.get(), reflect.ValueOf(v))
+	return nil
+}
+
+func newTextValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "text",
+		stringFn:      textValueString,
+		setFn:         textValueSet,
+	}
+}
+
+func textValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.IsZero() {
+		return ""
+	}
+	return textString(v)
+}
+
+func textValueSet(val *Value, to string) error {
+	v, err := parseText(elemIfPtrType(val.typ()), to)
+	if err != nil {
+		return err
+	}
+	reflectValueSet(val.get(), v)
+	return nil
+}
+
+func newJSONValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "JSON",
+		stringFn:      jsonValueString,
+		setFn:         jsonValueSet,
+	}
+}
+
+func jsonValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.IsZero() {
+		return ""
+	}
+	b, err := json.Marshal(addressable(v).Addr().Interface())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func jsonValueSet(val *Value, to string) error {
+	t := elemIfPtrType(val.typ())
+	v := reflect.New(t)
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
+		return err
+	}
+	reflectValueSet(val.get(), v)
+	return nil
+}
+
 func newMapValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
 	return &Value{
 		base:          base,
@@ -803,15 +1391,63 @@ func mapValueString(val *Value) string {
 }
 
 func mapValueSet(val *Value, to string) error {
+	if !strings.HasPrefix(strings.TrimSpace(to), "{") {
+		return mapValueSetEntries(val, to)
+	}
 	t := elemIfPtrType(val.typ())
 	v := addressable(reflect.MakeMap(t)).Addr()
-	if err := json.Unmarshal([]byte(to), v.Interface()); err != nil {
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
 		return err
 	}
 	reflectValueSet(val.get(), v)
 	return nil
 }
 
+// mapValueSetEntries adds to the map comma separated key=value entries. Values are set as flag values of the map element type, so for slice elements they are appended to the list under the key.
+func mapValueSetEntries(val *Value, to string) error {
+	sep := val.separator
+	if sep == 0 {
+		sep = ','
+	}
+	entries, err := splitList(to, sep)
+	if err != nil {
+		return err
+	}
+	t := elemIfPtrType(val.typ())
+	m := reflect.MakeMap(t)
+	for iter := elemIfPtr(val.get()).MapRange(); iter.Next(); {
+		m.SetMapIndex(iter.Key(), iter.Value())
+	}
+	for _, entry := range entries {
+		k, v, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrMapEntry, entry)
+		}
+		key, err := parseMapKey(t.Key(), k)
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(elemIfPtrType(t.Elem()))
+		if x := m.MapIndex(key); x.IsValid() && (x.Kind() != reflect.Pointer || !x.IsNil()) {
+			elem.Elem().Set(elemIfPtr(x))
+		}
+		elemVal := New(elem.Interface())
+		if elemVal == nil {
+			return fmt.Errorf("%w: %s", errUnsupportedMapElem, t.Elem())
+		}
+		elemVal.SetAppend(true)
+		if err := elemVal.Set(v); err != nil {
+			return err
+		}
+		if t.Elem().Kind() != reflect.Pointer {
+			elem = elem.Elem()
+		}
+		m.SetMapIndex(key, elem)
+	}
+	reflectValueSet(val.get(), m)
+	return nil
+}
+
 func newStructValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
 	return &Value{
 		base:          base,
@@ -838,13 +1474,41 @@ func structValueString(val *Value) string {
 func structValueSet(val *Value, to string) error {
 	t := elemIfPtrType(val.typ())
 	v := reflect.New(t)
-	if err := json.Unmarshal([]byte(to), v.Interface()); err != nil {
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
 		return err
 	}
 	reflectValueSet(val.get(), v)
 	return nil
 }
 
+func newInterfaceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "JSON",
+		stringFn:      interfaceValueString,
+		setFn:         interfaceValueSet,
+	}
+}
+
+func interfaceValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.IsNil() {
+		return ""
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func interfaceValueSet(val *Value, to string) error {
+	reflectValueSet(val.get(), parseInterface(to))
+	return nil
+}
+
 func sliceValueString(val *Value) string {
 	v := elemIfPtr(val.get())
 	if v.Len() == 0 {
@@ -857,6 +1521,130 @@ func sliceValueString(val *Value) string {
 	return string(b)
 }
 
+func newArrayValue(typ reflect.Type, base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	if typ.Elem().Kind() == reflect.Uint8 { // array of bytes
+		return newBytesArrayValue(base, fieldsIndexes, fields)
+	}
+	elem := New(reflect.New(elemIfPtrType(typ.Elem())).Interface())
+	if elem == nil {
+		return nil
+	}
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      elem.Type() + " (JSON list)",
+		stringFn:      arrayValueString,
+		setFn:         arrayValueSet,
+		isBool:        elem.IsBoolFlag(),
+	}
+}
+
+func arrayValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.IsZero() {
+		return ""
+	}
+	switch elemIfPtrType(v.Type().Elem()) {
+	case durationType:
+		return durationSliceValueString(val)
+	case timeType:
+		return timeSliceValueString(val)
+	}
+	switch elemIfPtrType(v.Type().Elem()).Kind() { //nolint:exhaustive // cases for only types not supported by package "encoding/json"
+	case reflect.Complex64:
+		return complex64SliceValueString(val)
+	case reflect.Complex128:
+		return complex128SliceValueString(val)
+	}
+	return sliceValueString(val)
+}
+
+// arrayValueSet sets the whole array, if the provided string is a JSON list, or the next array element otherwise. Elements are set as flag values of the array element type, with tags of the array field (like 'layout').
+func arrayValueSet(val *Value, to string) error {
+	arrayType := elemIfPtrType(val.typ())
+	if strings.HasPrefix(strings.TrimSpace(to), "[") {
+		raw := elemIfPtrType(arrayType.Elem()).Kind() == reflect.Interface
+		elems, err := splitJSONList(to, raw, val.unmarshalJSON)
+		if err != nil {
+			return err
+		}
+		if len(elems) != arrayType.Len() {
+			return fmt.Errorf("%w: got %d, want %d", ErrArrayLength, len(elems), arrayType.Len())
+		}
+		v := reflect.New(arrayType)
+		for i, elem := range elems {
+			if err := arrayElemSet(val, v.Elem().Index(i), i, elem); err != nil {
+				return err
+			}
+		}
+		reflectValueSet(val.get(), v)
+		val.index = arrayType.Len()
+		return nil
+	}
+	if val.index >= arrayType.Len() {
+		return fmt.Errorf("%w: got more than %d", ErrArrayLength, arrayType.Len())
+	}
+	if err := arrayElemSet(val, elemIfPtr(val.get()).Index(val.index), val.index, to); err != nil {
+		return err
+	}
+	val.index++
+	return nil
+}
+
+// arrayElemSet sets the provided array element, as a flag value of the array element with the given index (see elemValue).
+func arrayElemSet(val *Value, dst reflect.Value, i int, to string) error {
+	v := reflect.New(elemIfPtrType(val.typ()).Elem()).Elem()
+	if err := val.elemValue(v, i).set(to, true); err != nil {
+		return err
+	}
+	reflectValueSet(dst, v)
+	return nil
+}
+
+// elemValue returns flag value of the provided element of the slice or array value, with the given index. The element field has the tag of the slice or array field, so that tags like 'layout' apply also to elements.
+func (val *Value) elemValue(v reflect.Value, i int) *Value {
+	f := elemField(strconv.Itoa(i), v.Type())
+	if len(val.fields) > 0 {
+		f.Tag = val.fields[len(val.fields)-1].Tag
+	}
+	elem := newValue(v, nil, nil, append(slices.Clone(val.fields), f))
+	elem.jsonDecodeFn = val.jsonDecodeFn
+	return elem
+}
+
+func newBytesArrayValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "base64",
+		stringFn:      bytesArrayValueString,
+		setFn:         bytesArrayValueSet,
+	}
+}
+
+func bytesArrayValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.IsZero() {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(addressable(v).Bytes())
+}
+
+func bytesArrayValueSet(val *Value, to string) error {
+	v, err := base64.StdEncoding.DecodeString(to)
+	if err != nil {
+		return err
+	}
+	x := elemIfPtr(val.get())
+	if len(v) != x.Len() {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrArrayLength, len(v), x.Len())
+	}
+	reflect.Copy(x, reflect.ValueOf(v))
+	return nil
+}
+
 func newBoolSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
 	return &Value{
 		base:          base,
@@ -1213,6 +2001,136 @@ func stringSliceValueSet(val *Value, to string) error {
 	reflectValueSet(x, reflectValueAppend(elemIfPtr(x), reflect.ValueOf(to)))
 	return nil
 }
+
+func newDurationSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "duration (JSON list)",
+		stringFn:      durationSliceValueString,
+		setFn:         durationSliceValueSet,
+	}
+}
+
+func durationSliceValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.Len() == 0 {
+		return ""
+	}
+	li := make([]string, 0, v.Len())
+	for _, x := range v.Seq2() {
+		li = append(li, `"`+time.Duration(elemIfPtr(x).Int()).String()+`"`)
+	}
+	return "[" + strings.Join(li, ",") + "]"
+}
+
+func durationSliceValueSet(val *Value, to string) error {
+	v, err := parseDuration(to)
+	if err != nil {
+		return err
+	}
+	x := val.get()
+	reflectValueSet(x, reflectValueAppend(elemIfPtr(x), reflect.ValueOf(v)))
+	return nil
+}
+
+func newTimeSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "time (JSON list)",
+		stringFn:      timeSliceValueString,
+		setFn:         timeSliceValueSet,
+	}
+}
+
+func timeSliceValueString(val *Value) string {
+	v := elemIfPtr(val.get())
+	if v.Len() == 0 {
+		return ""
+	}
+	layout := val.layout()
+	li := make([]string, 0, v.Len())
+	for _, x := range v.Seq2() {
+		li = append(li, strconv.Quote(elemIfPtr(x).Interface().(time.Time).Format(layout))) //nolint:forcetypeassert // no nee to check type conversion result - correct value should be selected by the newValue function
+	}
+	return "[" + strings.Join(li, ",") + "]"
+}
+
+func timeSliceValueSet(val *Value, to string) error {
+	v, err := parseTime(val.layout(), to)
+	if err != nil {
+		return err
+	}
+	x := val.get()
+	reflectValueSet(x, reflectValueAppend(elemIfPtr(x), reflect.ValueOf(v)))
+	return nil
+}
+
+func newTextSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "text (JSON list)",
+		stringFn:      sliceValueString,
+		setFn:         textSliceValueSet,
+	}
+}
+
+func textSliceValueSet(val *Value, to string) error {
+	sliceType := elemIfPtrType(val.typ())
+	v, err := parseText(elemIfPtrType(sliceType.Elem()), to)
+	if err != nil {
+		return err
+	}
+	x := val.get()
+	reflectValueSet(x, reflectValueAppend(elemIfPtr(x), v))
+	return nil
+}
+
+func newJSONSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "JSON (JSON list)",
+		stringFn:      sliceValueString,
+		setFn:         jsonSliceValueSet,
+	}
+}
+
+func jsonSliceValueSet(val *Value, to string) error {
+	sliceType := elemIfPtrType(val.typ())
+	t := elemIfPtrType(sliceType.Elem()) // slice element type
+	v := reflect.New(t)
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
+		return err
+	}
+	x := val.get()
+	reflectValueSet(x, reflectValueAppend(elemIfPtr(x), v))
+	return nil
+}
+
+func newInterfaceSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
+	return &Value{
+		base:          base,
+		fieldsIndexes: fieldsIndexes,
+		fields:        fields,
+		typeName:      "JSON (JSON list)",
+		stringFn:      sliceValueString,
+		setFn:         interfaceSliceValueSet,
+	}
+}
+
+func interfaceSliceValueSet(val *Value, to string) error {
+	x := val.get()
+	reflectValueSet(x, reflectValueAppend(elemIfPtr(x), parseInterface(to)))
+	return nil
+}
+
 func newBytesSliceValue(base reflect.Value, fieldsIndexes []int, fields []reflect.StructField) *Value {
 	return &Value{
 		base:          base,
@@ -1249,7 +2167,7 @@ func sliceSliceValueSet(val *Value, to string) error {
 	sliceType := elemIfPtrType(val.typ())
 	t := elemIfPtrType(sliceType.Elem()) // slice element type
 	v := addressable(reflect.MakeSlice(t, 0, 0)).Addr()
-	if err := json.Unmarshal([]byte(to), v.Interface()); err != nil {
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
 		return err
 	}
 	x := val.get()
@@ -1272,7 +2190,7 @@ func mapSliceValueSet(val *Value, to string) error {
 	sliceType := elemIfPtrType(val.typ())
 	t := elemIfPtrType(sliceType.Elem()) // slice element type
 	v := addressable(reflect.MakeMap(t)).Addr()
-	if err := json.Unmarshal([]byte(to), v.Interface()); err != nil {
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
 		return err
 	}
 	x := val.get()
@@ -1295,7 +2213,7 @@ func structSliceValueSet(val *Value, to string) error {
 	sliceType := elemIfPtrType(val.typ())
 	t := elemIfPtrType(sliceType.Elem()) // slice element type
 	v := reflect.New(t)
-	if err := json.Unmarshal([]byte(to), v.Interface()); err != nil {
+	if err := val.unmarshalJSON(to, v.Interface()); err != nil {
 		return err
 	}
 	x := val.get()
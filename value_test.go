@@ -15,11 +15,14 @@
 package jsonflag_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -136,6 +139,10 @@ func cloneValue(x reflect.Value) reflect.Value {
 
 	case reflect.Struct:
 		c := reflect.New(x.Type()).Elem()
+		if !exportedFieldsOnly(x.Type()) { // types with internal state (eg. time.Time) are copied as a whole
+			c.Set(x)
+			return c
+		}
 		for i, t := 0, x.Type(); i < t.NumField(); i++ {
 			if !t.Field(i).IsExported() {
 				continue
@@ -154,6 +161,15 @@ func cloneValue(x reflect.Value) reflect.Value {
 	}
 }
 
+func exportedFieldsOnly(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if !t.Field(i).IsExported() {
+			return false
+		}
+	}
+	return true
+}
+
 type TestBase struct {
 	SkipValue  int                                 `json:"SkipValue,omitempty"`
 	SkipPtr    *int                                `json:"SkipPtr,omitempty"`
@@ -192,6 +208,116 @@ type TestStruct struct {
 	Value string `json:"Value,omitempty"`
 }
 
+type TestMaps struct {
+	Servers map[string]TestStruct `json:"servers"`
+	Ports   map[int]int           `json:"ports"`
+}
+
+type TestSlices struct {
+	Servers  []TestStruct  `json:"servers"`
+	Pointers []*TestStruct `json:"pointers"`
+}
+
+type TestNode struct {
+	Value string    `json:"Value,omitempty"`
+	Next  *TestNode `json:"Next,omitempty"`
+}
+
+type TestEmbeddedBase struct {
+	Timeout  int    `json:"timeout,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Conflict string `json:"conflict,omitempty"`
+}
+
+type TestEmbeddedOther struct {
+	Conflict string `json:"conflict,omitempty"`
+	Tagged   string `json:",omitempty"`
+	Extra    string `json:"extra,omitempty"`
+}
+
+type TestEmbeddedTagged struct {
+	Renamed string `json:"Tagged,omitempty"`
+}
+
+type TestEmbedded struct {
+	TestEmbeddedBase
+	*TestEmbeddedOther
+	TestEmbeddedTagged
+	Name string `json:"name,omitempty"`
+}
+
+type TestLevel int
+
+func (l TestLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case 1:
+		return []byte("low"), nil
+	case 2:
+		return []byte("high"), nil
+	}
+	return nil, errors.New("unknown level")
+}
+
+func (l *TestLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type TestPoint struct {
+	X int
+	Y int
+}
+
+func (p *TestPoint) UnmarshalText(b []byte) error {
+	x, y, ok := strings.Cut(string(b), ",")
+	if !ok {
+		return errors.New("missing comma")
+	}
+	var err error
+	if p.X, err = strconv.Atoi(x); err != nil {
+		return err
+	}
+	p.Y, err = strconv.Atoi(y)
+	return err
+}
+
+type TestRange struct {
+	Min int
+	Max int
+}
+
+func (r TestRange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d..%d", r.Min, r.Max))
+}
+
+func (r *TestRange) UnmarshalJSON(b []byte) error {
+	s := ""
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if _, err := fmt.Sscanf(s, "%d..%d", &r.Min, &r.Max); err != nil {
+		return err
+	}
+	if r.Min > r.Max {
+		return errors.New("invalid range")
+	}
+	return nil
+}
+
+type TestJSONIgnored struct {
+	Ignored    string      `json:"-"`
+	IgnoredPtr *TestStruct `json:"-"`
+	Dash       string      `json:"-,"`
+	NotIgnored string      `json:"notIgnored"`
+}
+
 type TestUnexported struct {
 	unexported int `json:"unexported,omitempty"` //nolint:govet // tagged for testing purpose
 }
@@ -676,6 +802,147 @@ func TestRecursiveFlagValues(t *testing.T) {
 				{PathNames: []string{"Struct", "PtrToSliceOfPtrs"}, Type: "JSON object (JSON list)", Get: &[]*TestStruct{{Value: "a"}}, String: `[{"Value":"a"}]`},
 			},
 		},
+		{
+			name:      "self-referential",
+			given:     &TestNode{},
+			postGiven: &TestNode{Next: &TestNode{}},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestNode{}, String: ``},
+				{PathNames: []string{"Value"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"Next"}, Type: "JSON object", Get: &TestNode{}, String: ``},
+			},
+		},
+		{
+			name:      "max-depth",
+			filters:   []jsonflag.FilterFunc{jsonflag.MaxDepth(1)},
+			given:     &TestGenericType[TestStruct]{},
+			postGiven: &TestGenericType[TestStruct]{Ptr: &TestStruct{}, PtrToSliceOfValues: Ptr([]TestStruct(nil)), PtrToSliceOfPtrs: Ptr([]*TestStruct(nil))},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestGenericType[TestStruct]{}, String: `{"Value":{}}`},
+				{PathNames: []string{"Value"}, Type: "JSON object", Get: TestStruct{}, String: ``},
+				{PathNames: []string{"Ptr"}, Type: "JSON object", Get: &TestStruct{}, String: ``},
+				{PathNames: []string{"SliceOfValues"}, Type: "JSON object (JSON list)", Get: []TestStruct(nil), String: ``},
+				{PathNames: []string{"PtrToSliceOfValues"}, Type: "JSON object (JSON list)", Get: Ptr([]TestStruct(nil)), String: ``},
+				{PathNames: []string{"SliceOfPtrs"}, Type: "JSON object (JSON list)", Get: []*TestStruct(nil), String: ``},
+				{PathNames: []string{"PtrToSliceOfPtrs"}, Type: "JSON object (JSON list)", Get: Ptr([]*TestStruct(nil)), String: ``},
+			},
+		},
+		{
+			name:      "embedded",
+			given:     &TestEmbedded{},
+			postGiven: &TestEmbedded{TestEmbeddedOther: &TestEmbeddedOther{}},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestEmbedded{}, String: ``},
+				{PathNames: []string{"TestEmbeddedBase", "Timeout"}, Type: "int", Get: int(0), String: ``},
+				{PathNames: []string{"TestEmbeddedOther", "Extra"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"TestEmbeddedTagged", "Renamed"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"Name"}, Type: "string", Get: "", String: ``},
+			},
+		},
+		{
+			name: "text",
+			filters: []jsonflag.FilterFunc{
+				func(val *jsonflag.Value) jsonflag.FilterResult { // skip base
+					if len(val.Path()) == 0 {
+						return jsonflag.SkipAndDescend
+					}
+					return jsonflag.IncludeAndDescend
+				},
+			},
+			given:     &TestGenericType[TestPoint]{},
+			postGiven: &TestGenericType[TestPoint]{Ptr: &TestPoint{}, PtrToSliceOfValues: Ptr([]TestPoint(nil)), PtrToSliceOfPtrs: Ptr([]*TestPoint(nil))},
+			want: []*FlagValueData{
+				{PathNames: []string{"Value"}, Type: "text", Get: TestPoint{}, String: ``},
+				{PathNames: []string{"Ptr"}, Type: "text", Get: &TestPoint{}, String: ``},
+				{PathNames: []string{"SliceOfValues"}, Type: "text (JSON list)", Get: []TestPoint(nil), String: ``},
+				{PathNames: []string{"PtrToSliceOfValues"}, Type: "text (JSON list)", Get: Ptr([]TestPoint(nil)), String: ``},
+				{PathNames: []string{"SliceOfPtrs"}, Type: "text (JSON list)", Get: []*TestPoint(nil), String: ``},
+				{PathNames: []string{"PtrToSliceOfPtrs"}, Type: "text (JSON list)", Get: Ptr([]*TestPoint(nil)), String: ``},
+			},
+		},
+		{
+			name:      "json-unmarshaler",
+			given:     &struct{ Range TestRange }{},
+			postGiven: &struct{ Range TestRange }{},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &struct{ Range TestRange }{}, String: `{"Range":"0..0"}`},
+				{PathNames: []string{"Range"}, Type: "JSON", Get: TestRange{}, String: ``},
+			},
+		},
+		{
+			name:      "json-unmarshaler-descend",
+			filters:   []jsonflag.FilterFunc{jsonflag.DescendJSONUnmarshalers},
+			given:     &struct{ Range TestRange }{},
+			postGiven: &struct{ Range TestRange }{},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &struct{ Range TestRange }{}, String: `{"Range":"0..0"}`},
+				{PathNames: []string{"Range"}, Type: "JSON", Get: TestRange{}, String: ``},
+				{PathNames: []string{"Range", "Min"}, Type: "int", Get: int(0), String: ``},
+				{PathNames: []string{"Range", "Max"}, Type: "int", Get: int(0), String: ``},
+			},
+		},
+		{
+			name:      "json-ignored",
+			given:     &TestJSONIgnored{},
+			postGiven: &TestJSONIgnored{},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestJSONIgnored{}, String: `{"-":"","notIgnored":""}`},
+				{PathNames: []string{"Dash"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"NotIgnored"}, Type: "string", Get: "", String: ``},
+			},
+		},
+		{
+			name:      "json-ignored-included",
+			filters:   []jsonflag.FilterFunc{jsonflag.IncludeJSONIgnored},
+			given:     &TestJSONIgnored{},
+			postGiven: &TestJSONIgnored{IgnoredPtr: &TestStruct{}},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestJSONIgnored{}, String: `{"-":"","notIgnored":""}`},
+				{PathNames: []string{"Ignored"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"IgnoredPtr"}, Type: "JSON object", Get: &TestStruct{}, String: ``},
+				{PathNames: []string{"IgnoredPtr", "Value"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"Dash"}, Type: "string", Get: "", String: ``},
+				{PathNames: []string{"NotIgnored"}, Type: "string", Get: "", String: ``},
+			},
+		},
+		{
+			name:    "expand-maps",
+			filters: []jsonflag.FilterFunc{jsonflag.ExpandMaps},
+			given: &TestMaps{
+				Servers: map[string]TestStruct{"us": {Value: "b"}, "eu": {Value: "a"}},
+				Ports:   map[int]int{10: 2, 9: 1},
+			},
+			postGiven: &TestMaps{
+				Servers: map[string]TestStruct{"us": {Value: "b"}, "eu": {Value: "a"}},
+				Ports:   map[int]int{10: 2, 9: 1},
+			},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestMaps{Servers: map[string]TestStruct{"us": {Value: "b"}, "eu": {Value: "a"}}, Ports: map[int]int{10: 2, 9: 1}}, String: `{"servers":{"eu":{"Value":"a"},"us":{"Value":"b"}},"ports":{"10":2,"9":1}}`},
+				{PathNames: []string{"Servers"}, Type: "JSON object", Get: map[string]TestStruct{"us": {Value: "b"}, "eu": {Value: "a"}}, String: `{"eu":{"Value":"a"},"us":{"Value":"b"}}`},
+				{PathNames: []string{"Servers", "eu"}, Type: "JSON object", Get: TestStruct{Value: "a"}, String: `{"Value":"a"}`},
+				{PathNames: []string{"Servers", "eu", "Value"}, Type: "string", Get: "a", String: `a`},
+				{PathNames: []string{"Servers", "us"}, Type: "JSON object", Get: TestStruct{Value: "b"}, String: `{"Value":"b"}`},
+				{PathNames: []string{"Servers", "us", "Value"}, Type: "string", Get: "b", String: `b`},
+				{PathNames: []string{"Ports"}, Type: "JSON object", Get: map[int]int{10: 2, 9: 1}, String: `{"10":2,"9":1}`},
+				{PathNames: []string{"Ports", "10"}, Type: "int", Get: 2, String: `2`},
+				{PathNames: []string{"Ports", "9"}, Type: "int", Get: 1, String: `1`},
+			},
+		},
+		{
+			name:      "expand-slices",
+			filters:   []jsonflag.FilterFunc{jsonflag.ExpandSlices},
+			given:     &TestSlices{Servers: []TestStruct{{Value: "a"}, {Value: "b"}}},
+			postGiven: &TestSlices{Servers: []TestStruct{{Value: "a"}, {Value: "b"}}},
+			want: []*FlagValueData{
+				{PathNames: []string{}, Type: "JSON object", Get: &TestSlices{Servers: []TestStruct{{Value: "a"}, {Value: "b"}}}, String: `{"servers":[{"Value":"a"},{"Value":"b"}],"pointers":null}`},
+				{PathNames: []string{"Servers"}, Type: "JSON object (JSON list)", Get: []TestStruct{{Value: "a"}, {Value: "b"}}, String: `[{"Value":"a"},{"Value":"b"}]`},
+				{PathNames: []string{"Servers", "0"}, Type: "JSON object", Get: TestStruct{Value: "a"}, String: `{"Value":"a"}`},
+				{PathNames: []string{"Servers", "0", "Value"}, Type: "string", Get: "a", String: `a`},
+				{PathNames: []string{"Servers", "1"}, Type: "JSON object", Get: TestStruct{Value: "b"}, String: `{"Value":"b"}`},
+				{PathNames: []string{"Servers", "1", "Value"}, Type: "string", Get: "b", String: `b`},
+				{PathNames: []string{"Pointers"}, Type: "JSON object (JSON list)", Get: []*TestStruct(nil), String: ``},
+			},
+		},
 		{
 			name: "unexported",
 			filters: []jsonflag.FilterFunc{
@@ -724,6 +991,7 @@ func TestNewFlagValues(t *testing.T) {
 		noFlagValue bool
 		encoder     func(any) ([]byte, error)
 		decoder     func([]byte, any) error
+		appendOnly  bool
 		pre         *FlagValueData
 		setTo       string
 		setError    string
@@ -1123,10 +1391,84 @@ func TestNewFlagValues(t *testing.T) {
 			name:     "map/invalid-value",
 			given:    Ptr(map[string]string(nil)),
 			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
-			setTo:    `invalid`,
+			setTo:    `{invalid}`,
 			setError: "invalid character",
 			want:     &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
 		},
+		{
+			name:  "map/entries",
+			given: Ptr(map[string]string{"w": "z"}),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string{"w": "z"}), String: `{"w":"z"}`},
+			setTo: `k=a,"l=b,c",w=`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string{"k": "a", "l": "b,c", "w": ""}), String: `{"k":"a","l":"b,c","w":""}`},
+		},
+		{
+			name:  "map/entries-int-keys",
+			given: Ptr(map[int]float64(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[int]float64(nil)), String: ``},
+			setTo: `1=1.5,2=2.5`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[int]float64{1: 1.5, 2: 2.5}), String: `{"1":1.5,"2":2.5}`},
+		},
+		{
+			name:  "map/entries-text-keys",
+			given: Ptr(map[TestLevel]bool(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[TestLevel]bool(nil)), String: ``},
+			setTo: `high=true`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[TestLevel]bool{2: true}), String: `{"high":true}`},
+		},
+		{
+			name:     "map/entries-invalid-key",
+			given:    Ptr(map[int]string(nil)),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[int]string(nil)), String: ``},
+			setTo:    `1=a,x=b`,
+			setError: `strconv.ParseInt: parsing "x": invalid syntax`,
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[int]string(nil)), String: ``},
+		},
+		{
+			name:     "map/entries-invalid-element",
+			given:    Ptr(map[string]int{"w": 1}),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]int{"w": 1}), String: `{"w":1}`},
+			setTo:    `k=a`,
+			setError: `strconv.ParseInt: parsing "a": invalid syntax`,
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]int{"w": 1}), String: `{"w":1}`},
+		},
+		{
+			name:     "map/entries-missing-separator",
+			given:    Ptr(map[string]string(nil)),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
+			setTo:    `k`,
+			setError: `jsonflag: invalid map entry, want key=value: "k"`,
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
+		},
+		{
+			name:  "mapOfSlices/entries",
+			given: Ptr(map[string][]string{"k": {"a"}}),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string][]string{"k": {"a"}}), String: `{"k":["a"]}`},
+			setTo: `k=b,l=c,k=d`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string][]string{"k": {"a", "b", "d"}, "l": {"c"}}), String: `{"k":["a","b","d"],"l":["c"]}`},
+		},
+		{
+			name:  "map/entries-bare-quotes",
+			given: Ptr(map[string]string(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
+			setTo: `q=say "hi",k=a`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string{"q": `say "hi"`, "k": "a"}), String: `{"k":"a","q":"say \"hi\""}`},
+		},
+		{
+			name:     "map/entries-unterminated-quote",
+			given:    Ptr(map[string]string(nil)),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
+			setTo:    `k=a,"l=b`,
+			setError: `unterminated quoted list value: "l=b`,
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]string(nil)), String: ``},
+		},
+		{
+			name:  "mapOfStructs/entries",
+			given: Ptr(map[string]TestLoadElemsServer{"eu": {Host: "a", Port: 80}}),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]TestLoadElemsServer{"eu": {Host: "a", Port: 80}}), String: `{"eu":{"host":"a","port":80,"maxConns":0}}`},
+			setTo: `eu={"host":"x"},us={"host":"y","port":443}`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]TestLoadElemsServer{"eu": {Host: "x"}, "us": {Host: "y", Port: 443}}), String: `{"eu":{"host":"x","port":0,"maxConns":0},"us":{"host":"y","port":443,"maxConns":0}}`},
+		},
 		{
 			name:  "struct/empty",
 			given: &TestBase{},
@@ -1157,11 +1499,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "bool (JSON list)", Get: Ptr([]bool{true}), String: `[true]`},
 		},
 		{
-			name:  "sliceOfBools/non-empty",
-			given: Ptr([]bool{true}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "bool (JSON list)", Get: Ptr([]bool{true}), String: `[true]`},
-			setTo: "true",
-			want:  &FlagValueData{PathNames: []string{}, Type: "bool (JSON list)", Get: Ptr([]bool{true, true}), String: `[true,true]`},
+			name:       "sliceOfBools/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]bool{true}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "bool (JSON list)", Get: Ptr([]bool{true}), String: `[true]`},
+			setTo:      "true",
+			want:       &FlagValueData{PathNames: []string{}, Type: "bool (JSON list)", Get: Ptr([]bool{true, true}), String: `[true,true]`},
 		},
 		{
 			name:     "sliceOfBools/invalid-value",
@@ -1183,7 +1526,23 @@ func TestNewFlagValues(t *testing.T) {
 			given: Ptr([]int{1}),
 			pre:   &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{1}), String: `[1]`},
 			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{1, 2}), String: `[1,2]`},
+			want:  &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{2}), String: `[2]`},
+		},
+		{
+			name:     "sliceOfInts/non-empty-invalid-value",
+			given:    Ptr([]int{1}),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{1}), String: `[1]`},
+			setTo:    "invalid",
+			setError: "invalid syntax",
+			want:     &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{1}), String: `[1]`},
+		},
+		{
+			name:       "sliceOfInts/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]int{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: Ptr([]int{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfInts/invalid-value",
@@ -1201,11 +1560,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "int8 (JSON list)", Get: Ptr([]int8{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfInt8s/non-empty",
-			given: Ptr([]int8{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "int8 (JSON list)", Get: Ptr([]int8{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "int8 (JSON list)", Get: Ptr([]int8{1, 2}), String: `[1,2]`},
+			name:       "sliceOfInt8s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]int8{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "int8 (JSON list)", Get: Ptr([]int8{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "int8 (JSON list)", Get: Ptr([]int8{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfInt8s/invalid-value",
@@ -1223,11 +1583,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "int16 (JSON list)", Get: Ptr([]int16{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfInt16s/non-empty",
-			given: Ptr([]int16{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "int16 (JSON list)", Get: Ptr([]int16{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "int16 (JSON list)", Get: Ptr([]int16{1, 2}), String: `[1,2]`},
+			name:       "sliceOfInt16s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]int16{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "int16 (JSON list)", Get: Ptr([]int16{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "int16 (JSON list)", Get: Ptr([]int16{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfInt16s/invalid-value",
@@ -1245,11 +1606,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "int32 (JSON list)", Get: Ptr([]int32{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfInt32s/non-empty",
-			given: Ptr([]int32{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "int32 (JSON list)", Get: Ptr([]int32{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "int32 (JSON list)", Get: Ptr([]int32{1, 2}), String: `[1,2]`},
+			name:       "sliceOfInt32s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]int32{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "int32 (JSON list)", Get: Ptr([]int32{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "int32 (JSON list)", Get: Ptr([]int32{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfInt32s/invalid-value",
@@ -1267,11 +1629,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "int64 (JSON list)", Get: Ptr([]int64{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfInt64s/non-empty",
-			given: Ptr([]int64{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "int64 (JSON list)", Get: Ptr([]int64{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "int64 (JSON list)", Get: Ptr([]int64{1, 2}), String: `[1,2]`},
+			name:       "sliceOfInt64s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]int64{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "int64 (JSON list)", Get: Ptr([]int64{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "int64 (JSON list)", Get: Ptr([]int64{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfInt64s/invalid-value",
@@ -1289,11 +1652,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "uint (JSON list)", Get: Ptr([]uint{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfUints/non-empty",
-			given: Ptr([]uint{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "uint (JSON list)", Get: Ptr([]uint{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "uint (JSON list)", Get: Ptr([]uint{1, 2}), String: `[1,2]`},
+			name:       "sliceOfUints/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]uint{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "uint (JSON list)", Get: Ptr([]uint{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "uint (JSON list)", Get: Ptr([]uint{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfUints/invalid-value",
@@ -1311,11 +1675,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "uint8 (JSON list)", Get: Ptr([]*uint8{Ptr(uint8(2))}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfUint8s/non-empty",
-			given: Ptr([]*uint8{Ptr(uint8(1))}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "uint8 (JSON list)", Get: Ptr([]*uint8{Ptr(uint8(1))}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "uint8 (JSON list)", Get: Ptr([]*uint8{Ptr(uint8(1)), Ptr(uint8(2))}), String: `[1,2]`},
+			name:       "sliceOfUint8s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]*uint8{Ptr(uint8(1))}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "uint8 (JSON list)", Get: Ptr([]*uint8{Ptr(uint8(1))}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "uint8 (JSON list)", Get: Ptr([]*uint8{Ptr(uint8(1)), Ptr(uint8(2))}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfUint8s/invalid-value",
@@ -1333,11 +1698,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "uint16 (JSON list)", Get: Ptr([]uint16{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfUint16s/non-empty",
-			given: Ptr([]uint16{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "uint16 (JSON list)", Get: Ptr([]uint16{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "uint16 (JSON list)", Get: Ptr([]uint16{1, 2}), String: `[1,2]`},
+			name:       "sliceOfUint16s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]uint16{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "uint16 (JSON list)", Get: Ptr([]uint16{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "uint16 (JSON list)", Get: Ptr([]uint16{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfUint16s/invalid-value",
@@ -1355,11 +1721,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "uint32 (JSON list)", Get: Ptr([]uint32{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfUint32s/non-empty",
-			given: Ptr([]uint32{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "uint32 (JSON list)", Get: Ptr([]uint32{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "uint32 (JSON list)", Get: Ptr([]uint32{1, 2}), String: `[1,2]`},
+			name:       "sliceOfUint32s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]uint32{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "uint32 (JSON list)", Get: Ptr([]uint32{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "uint32 (JSON list)", Get: Ptr([]uint32{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfUint32s/invalid-value",
@@ -1377,11 +1744,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "uint64 (JSON list)", Get: Ptr([]uint64{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfUint64s/non-empty",
-			given: Ptr([]uint64{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "uint64 (JSON list)", Get: Ptr([]uint64{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "uint64 (JSON list)", Get: Ptr([]uint64{1, 2}), String: `[1,2]`},
+			name:       "sliceOfUint64s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]uint64{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "uint64 (JSON list)", Get: Ptr([]uint64{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "uint64 (JSON list)", Get: Ptr([]uint64{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfUint64s/invalid-value",
@@ -1399,11 +1767,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "float32 (JSON list)", Get: Ptr([]float32{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfFloat32s/non-empty",
-			given: Ptr([]float32{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "float32 (JSON list)", Get: Ptr([]float32{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "float32 (JSON list)", Get: Ptr([]float32{1, 2}), String: `[1,2]`},
+			name:       "sliceOfFloat32s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]float32{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "float32 (JSON list)", Get: Ptr([]float32{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "float32 (JSON list)", Get: Ptr([]float32{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfFloat32s/invalid-value",
@@ -1421,11 +1790,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "float64 (JSON list)", Get: Ptr([]float64{2}), String: `[2]`},
 		},
 		{
-			name:  "sliceOfFloat64s/non-empty",
-			given: Ptr([]float64{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "float64 (JSON list)", Get: Ptr([]float64{1}), String: `[1]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "float64 (JSON list)", Get: Ptr([]float64{1, 2}), String: `[1,2]`},
+			name:       "sliceOfFloat64s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]float64{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "float64 (JSON list)", Get: Ptr([]float64{1}), String: `[1]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "float64 (JSON list)", Get: Ptr([]float64{1, 2}), String: `[1,2]`},
 		},
 		{
 			name:     "sliceOfFloat64s/invalid-value",
@@ -1443,11 +1813,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: Ptr([]complex64{2}), String: `["(2+0i)"]`},
 		},
 		{
-			name:  "sliceOfComplex64s/non-empty",
-			given: Ptr([]complex64{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: Ptr([]complex64{1}), String: `["(1+0i)"]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: Ptr([]complex64{1, 2}), String: `["(1+0i)","(2+0i)"]`},
+			name:       "sliceOfComplex64s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]complex64{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: Ptr([]complex64{1}), String: `["(1+0i)"]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: Ptr([]complex64{1, 2}), String: `["(1+0i)","(2+0i)"]`},
 		},
 		{
 			name:     "sliceOfComplex64s/invalid-value",
@@ -1465,11 +1836,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "complex128 (JSON list)", Get: Ptr([]complex128{2}), String: `["(2+0i)"]`},
 		},
 		{
-			name:  "sliceOfComplex128s/non-empty",
-			given: Ptr([]complex128{1}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "complex128 (JSON list)", Get: Ptr([]complex128{1}), String: `["(1+0i)"]`},
-			setTo: "2",
-			want:  &FlagValueData{PathNames: []string{}, Type: "complex128 (JSON list)", Get: Ptr([]complex128{1, 2}), String: `["(1+0i)","(2+0i)"]`},
+			name:       "sliceOfComplex128s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]complex128{1}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "complex128 (JSON list)", Get: Ptr([]complex128{1}), String: `["(1+0i)"]`},
+			setTo:      "2",
+			want:       &FlagValueData{PathNames: []string{}, Type: "complex128 (JSON list)", Get: Ptr([]complex128{1, 2}), String: `["(1+0i)","(2+0i)"]`},
 		},
 		{
 			name:     "sliceOfComplex128s/invalid-value",
@@ -1487,11 +1859,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "string (JSON list)", Get: Ptr([]string{"a"}), String: `["a"]`},
 		},
 		{
-			name:  "sliceOfStrings/non-empty",
-			given: Ptr([]string{"z"}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "string (JSON list)", Get: Ptr([]string{"z"}), String: `["z"]`},
-			setTo: "a",
-			want:  &FlagValueData{PathNames: []string{}, Type: "string (JSON list)", Get: Ptr([]string{"z", "a"}), String: `["z","a"]`},
+			name:       "sliceOfStrings/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]string{"z"}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "string (JSON list)", Get: Ptr([]string{"z"}), String: `["z"]`},
+			setTo:      "a",
+			want:       &FlagValueData{PathNames: []string{}, Type: "string (JSON list)", Get: Ptr([]string{"z", "a"}), String: `["z","a"]`},
 		},
 		{
 			name:  "sliceOfBase64s/empty",
@@ -1501,11 +1874,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "base64 (JSON list)", Get: Ptr([][]byte{{2}}), String: `["Ag=="]`},
 		},
 		{
-			name:  "sliceOfBase64s/non-empty",
-			given: Ptr([][]byte{{1}}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "base64 (JSON list)", Get: Ptr([][]byte{{1}}), String: `["AQ=="]`},
-			setTo: "Ag==",
-			want:  &FlagValueData{PathNames: []string{}, Type: "base64 (JSON list)", Get: Ptr([][]byte{{1}, {2}}), String: `["AQ==","Ag=="]`},
+			name:       "sliceOfBase64s/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([][]byte{{1}}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "base64 (JSON list)", Get: Ptr([][]byte{{1}}), String: `["AQ=="]`},
+			setTo:      "Ag==",
+			want:       &FlagValueData{PathNames: []string{}, Type: "base64 (JSON list)", Get: Ptr([][]byte{{1}, {2}}), String: `["AQ==","Ag=="]`},
 		},
 		{
 			name:     "sliceOfBase64s/invalid-value",
@@ -1523,11 +1897,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]map[string]string{{"k": "a"}}), String: `[{"k":"a"}]`},
 		},
 		{
-			name:  "sliceOfMaps/non-empty",
-			given: Ptr([]map[string]string{{"w": "z"}}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]map[string]string{{"w": "z"}}), String: `[{"w":"z"}]`},
-			setTo: `{"k":"a"}`,
-			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]map[string]string{{"w": "z"}, {"k": "a"}}), String: `[{"w":"z"},{"k":"a"}]`},
+			name:       "sliceOfMaps/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]map[string]string{{"w": "z"}}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]map[string]string{{"w": "z"}}), String: `[{"w":"z"}]`},
+			setTo:      `{"k":"a"}`,
+			want:       &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]map[string]string{{"w": "z"}, {"k": "a"}}), String: `[{"w":"z"},{"k":"a"}]`},
 		},
 		{
 			name:     "sliceOfMaps/invalid-value",
@@ -1545,11 +1920,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]*TestBase{{String: &TestGenericType[string]{Value: "a"}}}), String: `[{"String":{"Value":"a"}}]`},
 		},
 		{
-			name:  "sliceOfStructs/non-empty",
-			given: Ptr([]*TestBase{{Bool: &TestGenericType[bool]{Value: true}}}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]*TestBase{{Bool: &TestGenericType[bool]{Value: true}}}), String: `[{"Bool":{"Value":true}}]`},
-			setTo: `{"String":{"Value":"a"}}`,
-			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]*TestBase{{Bool: &TestGenericType[bool]{Value: true}}, {String: &TestGenericType[string]{Value: "a"}}}), String: `[{"Bool":{"Value":true}},{"String":{"Value":"a"}}]`},
+			name:       "sliceOfStructs/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]*TestBase{{Bool: &TestGenericType[bool]{Value: true}}}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]*TestBase{{Bool: &TestGenericType[bool]{Value: true}}}), String: `[{"Bool":{"Value":true}}]`},
+			setTo:      `{"String":{"Value":"a"}}`,
+			want:       &FlagValueData{PathNames: []string{}, Type: "JSON object (JSON list)", Get: Ptr([]*TestBase{{Bool: &TestGenericType[bool]{Value: true}}, {String: &TestGenericType[string]{Value: "a"}}}), String: `[{"Bool":{"Value":true}},{"String":{"Value":"a"}}]`},
 		},
 		{
 			name:     "sliceOfStructs/invalid-value",
@@ -1567,11 +1943,12 @@ func TestNewFlagValues(t *testing.T) {
 			want:  &FlagValueData{PathNames: []string{}, Type: "JSON list", Get: Ptr([][]string{{"a"}}), String: `[["a"]]`},
 		},
 		{
-			name:  "sliceOfSlicesOfStrings/non-empty",
-			given: Ptr([][]string{{"z"}}),
-			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON list", Get: Ptr([][]string{{"z"}}), String: `[["z"]]`},
-			setTo: `["a"]`,
-			want:  &FlagValueData{PathNames: []string{}, Type: "JSON list", Get: Ptr([][]string{{"z"}, {"a"}}), String: `[["z"],["a"]]`},
+			name:       "sliceOfSlicesOfStrings/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([][]string{{"z"}}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "JSON list", Get: Ptr([][]string{{"z"}}), String: `[["z"]]`},
+			setTo:      `["a"]`,
+			want:       &FlagValueData{PathNames: []string{}, Type: "JSON list", Get: Ptr([][]string{{"z"}, {"a"}}), String: `[["z"],["a"]]`},
 		},
 		{
 			name:     "sliceOfSlicesOfStrings/invalid-value",
@@ -1581,6 +1958,285 @@ func TestNewFlagValues(t *testing.T) {
 			setError: "invalid character",
 			want:     &FlagValueData{PathNames: []string{}, Type: "JSON list", Get: Ptr([][]string(nil)), String: ``},
 		},
+		{
+			name:  "text/empty",
+			given: Ptr(TestLevel(0)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "text", Get: Ptr(TestLevel(0)), String: ``},
+			setTo: "high",
+			want:  &FlagValueData{PathNames: []string{}, Type: "text", Get: Ptr(TestLevel(2)), String: `high`},
+		},
+		{
+			name:  "text/non-empty",
+			given: Ptr(TestLevel(1)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "text", Get: Ptr(TestLevel(1)), String: `low`},
+			setTo: "high",
+			want:  &FlagValueData{PathNames: []string{}, Type: "text", Get: Ptr(TestLevel(2)), String: `high`},
+		},
+		{
+			name:     "text/invalid-value",
+			given:    Ptr(TestLevel(1)),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "text", Get: Ptr(TestLevel(1)), String: `low`},
+			setTo:    "invalid",
+			setError: "unknown level",
+			want:     &FlagValueData{PathNames: []string{}, Type: "text", Get: Ptr(TestLevel(1)), String: `low`},
+		},
+		{
+			name:  "text/struct-without-marshaler",
+			given: &TestPoint{},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "text", Get: &TestPoint{}, String: ``},
+			setTo: "1,2",
+			want:  &FlagValueData{PathNames: []string{}, Type: "text", Get: &TestPoint{X: 1, Y: 2}, String: `{1 2}`},
+		},
+		{
+			name:  "sliceOfTexts/empty",
+			given: Ptr([]TestLevel(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "text (JSON list)", Get: Ptr([]TestLevel(nil)), String: ``},
+			setTo: "high",
+			want:  &FlagValueData{PathNames: []string{}, Type: "text (JSON list)", Get: Ptr([]TestLevel{2}), String: `["high"]`},
+		},
+		{
+			name:       "sliceOfTexts/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]*TestLevel{Ptr(TestLevel(1))}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "text (JSON list)", Get: Ptr([]*TestLevel{Ptr(TestLevel(1))}), String: `["low"]`},
+			setTo:      "high",
+			want:       &FlagValueData{PathNames: []string{}, Type: "text (JSON list)", Get: Ptr([]*TestLevel{Ptr(TestLevel(1)), Ptr(TestLevel(2))}), String: `["low","high"]`},
+		},
+		{
+			name:     "sliceOfTexts/invalid-value",
+			given:    Ptr([]TestLevel{1}),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "text (JSON list)", Get: Ptr([]TestLevel{1}), String: `["low"]`},
+			setTo:    "invalid",
+			setError: "unknown level",
+			want:     &FlagValueData{PathNames: []string{}, Type: "text (JSON list)", Get: Ptr([]TestLevel{1}), String: `["low"]`},
+		},
+		{
+			name:  "duration/empty",
+			given: Ptr(time.Duration(0)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "duration", Get: Ptr(time.Duration(0)), String: ``},
+			setTo: "1m30s",
+			want:  &FlagValueData{PathNames: []string{}, Type: "duration", Get: Ptr(90 * time.Second), String: `1m30s`},
+		},
+		{
+			name:  "duration/non-empty",
+			given: Ptr(time.Second),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "duration", Get: Ptr(time.Second), String: `1s`},
+			setTo: "1m30s",
+			want:  &FlagValueData{PathNames: []string{}, Type: "duration", Get: Ptr(90 * time.Second), String: `1m30s`},
+		},
+		{
+			name:     "duration/invalid-value",
+			given:    Ptr(time.Second),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "duration", Get: Ptr(time.Second), String: `1s`},
+			setTo:    "1",
+			setError: "missing unit in duration",
+			want:     &FlagValueData{PathNames: []string{}, Type: "duration", Get: Ptr(time.Second), String: `1s`},
+		},
+		{
+			name:  "time/empty",
+			given: Ptr(time.Time{}),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "time", Get: Ptr(time.Time{}), String: ``},
+			setTo: "2025-01-02T03:04:05Z",
+			want:  &FlagValueData{PathNames: []string{}, Type: "time", Get: Ptr(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)), String: `2025-01-02T03:04:05Z`},
+		},
+		{
+			name:  "time/non-empty",
+			given: Ptr(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "time", Get: Ptr(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)), String: `2024-01-02T03:04:05.000000006Z`},
+			setTo: "2025-01-02T03:04:05Z",
+			want:  &FlagValueData{PathNames: []string{}, Type: "time", Get: Ptr(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)), String: `2025-01-02T03:04:05Z`},
+		},
+		{
+			name:     "time/invalid-value",
+			given:    Ptr(time.Time{}),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "time", Get: Ptr(time.Time{}), String: ``},
+			setTo:    "2025-01-02",
+			setError: "cannot parse",
+			want:     &FlagValueData{PathNames: []string{}, Type: "time", Get: Ptr(time.Time{}), String: ``},
+		},
+		{
+			name:  "sliceOfDurations/empty",
+			given: Ptr([]time.Duration(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "duration (JSON list)", Get: Ptr([]time.Duration(nil)), String: ``},
+			setTo: "2s",
+			want:  &FlagValueData{PathNames: []string{}, Type: "duration (JSON list)", Get: Ptr([]time.Duration{2 * time.Second}), String: `["2s"]`},
+		},
+		{
+			name:       "sliceOfDurations/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]*time.Duration{Ptr(time.Second)}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "duration (JSON list)", Get: Ptr([]*time.Duration{Ptr(time.Second)}), String: `["1s"]`},
+			setTo:      "2s",
+			want:       &FlagValueData{PathNames: []string{}, Type: "duration (JSON list)", Get: Ptr([]*time.Duration{Ptr(time.Second), Ptr(2 * time.Second)}), String: `["1s","2s"]`},
+		},
+		{
+			name:     "sliceOfDurations/invalid-value",
+			given:    Ptr([]time.Duration{time.Second}),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "duration (JSON list)", Get: Ptr([]time.Duration{time.Second}), String: `["1s"]`},
+			setTo:    "invalid",
+			setError: "invalid duration",
+			want:     &FlagValueData{PathNames: []string{}, Type: "duration (JSON list)", Get: Ptr([]time.Duration{time.Second}), String: `["1s"]`},
+		},
+		{
+			name:  "sliceOfTimes/empty",
+			given: Ptr([]time.Time(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "time (JSON list)", Get: Ptr([]time.Time(nil)), String: ``},
+			setTo: "2025-01-02T03:04:05Z",
+			want:  &FlagValueData{PathNames: []string{}, Type: "time (JSON list)", Get: Ptr([]time.Time{time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}), String: `["2025-01-02T03:04:05Z"]`},
+		},
+		{
+			name:     "sliceOfTimes/invalid-value",
+			given:    Ptr([]time.Time(nil)),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "time (JSON list)", Get: Ptr([]time.Time(nil)), String: ``},
+			setTo:    "invalid",
+			setError: "cannot parse",
+			want:     &FlagValueData{PathNames: []string{}, Type: "time (JSON list)", Get: Ptr([]time.Time(nil)), String: ``},
+		},
+		{
+			name:  "json/empty",
+			given: &TestRange{},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON", Get: &TestRange{}, String: ``},
+			setTo: `"1..5"`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON", Get: &TestRange{Min: 1, Max: 5}, String: `"1..5"`},
+		},
+		{
+			name:  "json/non-empty",
+			given: &TestRange{Min: 1, Max: 2},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON", Get: &TestRange{Min: 1, Max: 2}, String: `"1..2"`},
+			setTo: `"1..5"`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON", Get: &TestRange{Min: 1, Max: 5}, String: `"1..5"`},
+		},
+		{
+			name:     "json/invalid-value",
+			given:    &TestRange{Min: 1, Max: 2},
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON", Get: &TestRange{Min: 1, Max: 2}, String: `"1..2"`},
+			setTo:    `"5..1"`,
+			setError: "invalid range",
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON", Get: &TestRange{Min: 1, Max: 2}, String: `"1..2"`},
+		},
+		{
+			name:  "sliceOfJSONs/empty",
+			given: Ptr([]TestRange(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON (JSON list)", Get: Ptr([]TestRange(nil)), String: ``},
+			setTo: `"1..5"`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON (JSON list)", Get: Ptr([]TestRange{{Min: 1, Max: 5}}), String: `["1..5"]`},
+		},
+		{
+			name:     "sliceOfJSONs/invalid-value",
+			given:    Ptr([]*TestRange{{Min: 1, Max: 2}}),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON (JSON list)", Get: Ptr([]*TestRange{{Min: 1, Max: 2}}), String: `["1..2"]`},
+			setTo:    `"5..1"`,
+			setError: "invalid range",
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON (JSON list)", Get: Ptr([]*TestRange{{Min: 1, Max: 2}}), String: `["1..2"]`},
+		},
+		{
+			name:  "array/empty",
+			given: &[3]int{},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{}, String: ``},
+			setTo: "2",
+			want:  &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{2, 0, 0}, String: `[2,0,0]`},
+		},
+		{
+			name:  "array/json-list",
+			given: &[3]int{1, 1, 1},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{1, 1, 1}, String: `[1,1,1]`},
+			setTo: "[1,2,3]",
+			want:  &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{1, 2, 3}, String: `[1,2,3]`},
+		},
+		{
+			name:     "array/json-list-invalid-length",
+			given:    &[3]int{1, 1, 1},
+			pre:      &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{1, 1, 1}, String: `[1,1,1]`},
+			setTo:    "[1,2]",
+			setError: "invalid number of array elements: got 2, want 3",
+			want:     &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{1, 1, 1}, String: `[1,1,1]`},
+		},
+		{
+			name:     "array/invalid-value",
+			given:    &[3]int{},
+			pre:      &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{}, String: ``},
+			setTo:    "invalid",
+			setError: "invalid syntax",
+			want:     &FlagValueData{PathNames: []string{}, Type: "int (JSON list)", Get: &[3]int{}, String: ``},
+		},
+		{
+			name:  "arrayOfPtrs/empty",
+			given: &[2]*complex64{},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: &[2]*complex64{}, String: ``},
+			setTo: "1",
+			want:  &FlagValueData{PathNames: []string{}, Type: "complex64 (JSON list)", Get: &[2]*complex64{Ptr(complex64(1)), nil}, String: `["(1+0i)","(0+0i)"]`},
+		},
+		{
+			name:  "arrayOfBytes/empty",
+			given: &[2]byte{},
+			pre:   &FlagValueData{PathNames: []string{}, Type: "base64", Get: &[2]byte{}, String: ``},
+			setTo: "AQI=",
+			want:  &FlagValueData{PathNames: []string{}, Type: "base64", Get: &[2]byte{1, 2}, String: `AQI=`},
+		},
+		{
+			name:     "arrayOfBytes/invalid-length",
+			given:    &[2]byte{},
+			pre:      &FlagValueData{PathNames: []string{}, Type: "base64", Get: &[2]byte{}, String: ``},
+			setTo:    "AQ==",
+			setError: "invalid number of array elements: got 1 bytes, want 2",
+			want:     &FlagValueData{PathNames: []string{}, Type: "base64", Get: &[2]byte{}, String: ``},
+		},
+		{
+			name:  "interface/empty",
+			given: Ptr(any(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any(nil)), String: ``},
+			setTo: `{"a":[12345678901234567890,true]}`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any(map[string]any{"a": []any{json.Number("12345678901234567890"), true}})), String: `{"a":[12345678901234567890,true]}`},
+		},
+		{
+			name:  "interface/non-empty",
+			given: Ptr(any("z")),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any("z")), String: `"z"`},
+			setTo: `2`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any(json.Number("2"))), String: `2`},
+		},
+		{
+			name:  "interface/bare-string",
+			given: Ptr(any(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any(nil)), String: ``},
+			setTo: `a`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any("a")), String: `"a"`},
+		},
+		{
+			name:  "interface/null",
+			given: Ptr(any("z")),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any("z")), String: `"z"`},
+			setTo: `null`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON", Get: Ptr(any(nil)), String: ``},
+		},
+		{
+			name:  "mapOfInterfaces/empty",
+			given: Ptr(map[string]any(nil)),
+			pre:   &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]any(nil)), String: ``},
+			setTo: `{"a":12345678901234567890}`,
+			want:  &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]any{"a": json.Number("12345678901234567890")}), String: `{"a":12345678901234567890}`},
+		},
+		{
+			name:       "sliceOfInterfaces/non-empty-append",
+			appendOnly: true,
+			given:      Ptr([]any{"z"}),
+			pre:        &FlagValueData{PathNames: []string{}, Type: "JSON (JSON list)", Get: Ptr([]any{"z"}), String: `["z"]`},
+			setTo:      `1.5`,
+			want:       &FlagValueData{PathNames: []string{}, Type: "JSON (JSON list)", Get: Ptr([]any{"z", json.Number("1.5")}), String: `["z",1.5]`},
+		},
+		{
+			name:     "mapOfInterfaces/trailing-data",
+			given:    Ptr(map[string]any(nil)),
+			pre:      &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]any(nil)), String: ``},
+			setTo:    `{"a":1} {}`,
+			setError: "invalid data after top-level JSON value",
+			want:     &FlagValueData{PathNames: []string{}, Type: "JSON object", Get: Ptr(map[string]any(nil)), String: ``},
+		},
+		{
+			name:        "nonEmptyInterface",
+			given:       Ptr(error(nil)),
+			noFlagValue: true,
+		},
 		{
 			name:    "customEncoder/value",
 			given:   Ptr(int(1)),
@@ -1636,6 +2292,7 @@ func TestNewFlagValues(t *testing.T) {
 			if test.decoder != nil {
 				val.SetDecoder(test.decoder)
 			}
+			val.SetAppend(test.appendOnly)
 
 			RequireDataOfFlagValueEqual(t, test.pre, DataOfFlagValue(val))
 
@@ -1653,6 +2310,326 @@ func TestNewFlagValues(t *testing.T) {
 	}
 }
 
+func TestTimeLayout(t *testing.T) {
+	t.Parallel()
+	given := &struct {
+		Date  time.Time   `layout:"2006-01-02"`
+		Dates []time.Time `layout:"2006-01-02"`
+	}{}
+	values := jsonflag.Recursive(given, func(val *jsonflag.Value) jsonflag.FilterResult {
+		if len(val.Path()) == 0 {
+			return jsonflag.SkipAndDescend
+		}
+		return jsonflag.IncludeAndDescend
+	})
+	require.Len(t, values, 2)
+
+	require.NoError(t, values[0].Set("2025-01-02"))
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), given.Date)
+	require.Equal(t, "2025-01-02", values[0].String())
+	require.ErrorContains(t, values[0].Set("2025-01-02T03:04:05Z"), "extra text")
+
+	require.NoError(t, values[1].Set("2025-01-02"))
+	require.NoError(t, values[1].Set("2025-01-03"))
+	require.Equal(t, `["2025-01-02","2025-01-03"]`, values[1].String())
+}
+
+func TestQuotedFlagValues(t *testing.T) {
+	t.Parallel()
+	type Quoted struct {
+		Int      int           `json:"int,string"`
+		Bool     bool          `json:"bool,string"`
+		String   string        `json:"string,string"`
+		Duration time.Duration `json:"duration,string"`
+	}
+	given := &Quoted{}
+	values := jsonflag.Recursive(given)
+	require.Len(t, values, 5)
+
+	require.NoError(t, values[1].Set(`"2"`))
+	require.NoError(t, values[2].Set(`"true"`))
+	require.NoError(t, values[3].Set(`"a"`))
+	require.NoError(t, values[4].Set(`1s`))
+	require.Equal(t, &Quoted{Int: 2, Bool: true, String: `"a"`, Duration: time.Second}, given)
+	require.Equal(t, `2`, values[1].String())
+	require.Equal(t, `true`, values[2].String())
+	require.Equal(t, `"a"`, values[3].String())
+	require.Equal(t, `1s`, values[4].String())
+
+	require.NoError(t, values[1].Set(`3`))
+	require.Equal(t, 3, given.Int)
+	require.ErrorContains(t, values[1].Set(`"3`), "unexpected end of JSON input")
+
+	require.NoError(t, values[0].Set(`{"int":"4","bool":"false"}`))
+	require.Equal(t, `{"int":"4","bool":"false","string":"\"\"","duration":"0"}`, values[0].String())
+}
+
+func TestArrayFlagValues(t *testing.T) {
+	t.Parallel()
+	given := &[2]float64{}
+	val := jsonflag.New(given)
+	require.NoError(t, val.Set("1.5"))
+	require.NoError(t, val.Set("2.5"))
+	require.Equal(t, &[2]float64{1.5, 2.5}, given)
+
+	err := val.Set("3.5")
+	require.ErrorIs(t, err, jsonflag.ErrArrayLength)
+	require.EqualError(t, err, "jsonflag: invalid number of array elements: got more than 2")
+	require.Equal(t, &[2]float64{1.5, 2.5}, given)
+}
+
+func TestArrayRoundTrip(t *testing.T) {
+	t.Parallel()
+	type Arrays struct {
+		Durations [2]time.Duration `json:"durations"`
+		Complexes [2]complex128    `json:"complexes"`
+		Times     [1]time.Time     `json:"times" layout:"2006-01-02"`
+		Ints      [2]int           `json:"ints,string"`
+	}
+	given := &Arrays{
+		Durations: [2]time.Duration{time.Second, 2 * time.Second},
+		Complexes: [2]complex128{1 + 2i, 3},
+		Times:     [1]time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Ints:      [2]int{1, 2},
+	}
+	got := &Arrays{}
+	givenValues, gotValues := jsonflag.Recursive(given), jsonflag.Recursive(got)
+	require.Len(t, gotValues, 5)
+	for i := 1; i < len(givenValues); i++ {
+		require.NoError(t, gotValues[i].Set(givenValues[i].String()), "set %s", givenValues[i].String())
+	}
+	require.Equal(t, given, got)
+
+	got = &Arrays{}
+	gotValues = jsonflag.Recursive(got)
+	require.NoError(t, gotValues[3].Set("2024-01-02"))
+	require.NoError(t, gotValues[4].Set(`"1"`))
+	require.NoError(t, gotValues[4].Set("2"))
+	require.Equal(t, &Arrays{Times: given.Times, Ints: given.Ints}, got)
+}
+
+func TestSliceFlagValues(t *testing.T) {
+	t.Parallel()
+	given := &struct {
+		Hosts []string
+		Tags  []string `append:"true"`
+	}{Hosts: []string{"localhost"}, Tags: []string{"default"}}
+	defaults := given.Hosts
+	values := jsonflag.Recursive(given)
+	require.Len(t, values, 3)
+
+	require.NoError(t, values[1].Set("a"))
+	require.NoError(t, values[1].Set("b"))
+	require.Equal(t, []string{"a", "b"}, given.Hosts)
+	require.Equal(t, []string{"localhost"}, defaults)
+
+	require.NoError(t, values[2].Set("a"))
+	require.Equal(t, []string{"default", "a"}, given.Tags)
+}
+
+func TestSliceSeparatorFlagValues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		given    any
+		setTo    string
+		setError string
+		want     any
+	}{
+		{
+			name:  "ints/list",
+			given: Ptr([]int{1}),
+			setTo: "80,443",
+			want:  Ptr([]int{80, 443}),
+		},
+		{
+			name:  "ints/json-list",
+			given: Ptr([]int{1}),
+			setTo: "[80, 443]",
+			want:  Ptr([]int{80, 443}),
+		},
+		{
+			name:  "ints/empty",
+			given: Ptr([]int{1}),
+			setTo: "",
+			want:  Ptr([]int(nil)),
+		},
+		{
+			name:     "ints/invalid-value",
+			given:    Ptr([]int{1}),
+			setTo:    "80,invalid",
+			setError: `strconv.ParseInt: parsing "invalid": invalid syntax`,
+			want:     Ptr([]int{1}),
+		},
+		{
+			name:  "strings/quoted",
+			given: Ptr([]string(nil)),
+			setTo: `a,"b,c",""`,
+			want:  Ptr([]string{"a", "b,c", ""}),
+		},
+		{
+			name:  "strings/bare-quotes",
+			given: Ptr([]string(nil)),
+			setTo: `say "hi",b`,
+			want:  Ptr([]string{`say "hi"`, "b"}),
+		},
+		{
+			name:  "structs/list",
+			given: Ptr([]TestStruct(nil)),
+			setTo: `{"Value":"a,b"},{"Value":"c"}`,
+			want:  Ptr([]TestStruct{{Value: "a,b"}, {Value: "c"}}),
+		},
+		{
+			name:  "strings/json-list",
+			given: Ptr([]string(nil)),
+			setTo: `["a","b,c"]`,
+			want:  Ptr([]string{"a", "b,c"}),
+		},
+		{
+			name:     "strings/invalid-json-list",
+			given:    Ptr([]string{"a"}),
+			setTo:    `["a",`,
+			setError: "unexpected EOF",
+			want:     Ptr([]string{"a"}),
+		},
+		{
+			name:  "base64s/list",
+			given: Ptr([][]byte(nil)),
+			setTo: "AQ==,Ag==",
+			want:  Ptr([][]byte{{1}, {2}}),
+		},
+		{
+			name:  "complex128s/json-list",
+			given: Ptr([]complex128(nil)),
+			setTo: `["1+2i","3"]`,
+			want:  Ptr([]complex128{1 + 2i, 3}),
+		},
+		{
+			name:  "structs/json-list",
+			given: Ptr([]TestStruct(nil)),
+			setTo: `[{"Value":"a"},{"Value":"b"}]`,
+			want:  Ptr([]TestStruct{{Value: "a"}, {Value: "b"}}),
+		},
+		{
+			name:  "interfaces/json-list",
+			given: Ptr([]any(nil)),
+			setTo: `["1",1]`,
+			want:  Ptr([]any{"1", json.Number("1")}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			val := jsonflag.New(test.given)
+			val.SetSeparator(',')
+			err := val.Set(test.setTo)
+			if test.setError != "" {
+				require.EqualError(t, err, test.setError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.want, test.given)
+		})
+	}
+}
+
+func TestSliceSeparatorTag(t *testing.T) {
+	t.Parallel()
+	given := &struct {
+		Ports  []int    `sep:";"`
+		Labels []string `sep:";"`
+		Hosts  []string
+		Env    map[string]string `sep:";"`
+	}{}
+	values := jsonflag.Recursive(given)
+	require.Len(t, values, 5)
+
+	require.NoError(t, values[1].Set("80;443"))
+	require.NoError(t, values[1].Set("8080"))
+	require.NoError(t, values[2].Set("a,b;c"))
+	require.NoError(t, values[3].Set("a,b"))
+	require.NoError(t, values[4].Set("a=1,2;b=3"))
+	require.Equal(t, []int{80, 443, 8080}, given.Ports)
+	require.Equal(t, []string{"a,b", "c"}, given.Labels)
+	require.Equal(t, []string{"a,b"}, given.Hosts)
+	require.Equal(t, map[string]string{"a": "1,2", "b": "3"}, given.Env)
+}
+
+func TestMapEntryFlagValues(t *testing.T) {
+	t.Parallel()
+	given := &TestMaps{Servers: map[string]TestStruct{"eu": {Value: "a"}}}
+	values := jsonflag.Recursive(given, jsonflag.ExpandMaps)
+	require.Len(t, values, 5)
+	require.Equal(t, "Servers.eu.Value", jsonflag.Name(values[3].Path()))
+	require.Equal(t, "servers.eu.Value", jsonflag.JSONName(values[3].Path()))
+
+	require.NoError(t, values[3].Set("b"))
+	require.Equal(t, map[string]TestStruct{"eu": {Value: "b"}}, given.Servers)
+	require.NoError(t, values[2].Set(`{"Value":"c"}`))
+	require.Equal(t, "c", values[3].String())
+
+	given.Servers = nil
+	require.Equal(t, "", values[3].String())
+	require.NoError(t, values[3].Set("d"))
+	require.Equal(t, map[string]TestStruct{"eu": {Value: "d"}}, given.Servers)
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	given := &TestMaps{}
+	val := jsonflag.New(given)
+
+	require.NoError(t, val.SetPath([]string{"servers", "eu", "Value"}, "a"))
+	require.NoError(t, val.SetPath([]string{"ports", "80"}, "8080"))
+	require.Equal(t, &TestMaps{Servers: map[string]TestStruct{"eu": {Value: "a"}}, Ports: map[int]int{80: 8080}}, given)
+
+	sub, err := val.Lookup("servers", "us")
+	require.NoError(t, err)
+	require.Equal(t, "servers.us", jsonflag.JSONName(sub.Path()))
+	require.Equal(t, "", sub.String())
+	require.NoError(t, sub.Set(`{"Value":"b"}`))
+	require.Equal(t, TestStruct{Value: "b"}, given.Servers["us"])
+
+	nested := map[string]map[string]int{}
+	require.NoError(t, jsonflag.New(&nested).SetPath([]string{"a", "b"}, "1"))
+	require.Equal(t, map[string]map[string]int{"a": {"b": 1}}, nested)
+
+	_, err = val.Lookup("servers", "eu", "missing")
+	require.ErrorIs(t, err, jsonflag.ErrUnknownPath)
+	require.EqualError(t, err, "jsonflag: unknown path: servers.eu.missing")
+	_, err = val.Lookup("ports", "x")
+	require.EqualError(t, err, `strconv.ParseInt: parsing "x": invalid syntax: ports.x`)
+}
+
+func TestSliceElemFlagValues(t *testing.T) {
+	t.Parallel()
+	given := &TestSlices{Servers: []TestStruct{{Value: "a"}}}
+	values := jsonflag.Recursive(given, jsonflag.ExpandSlices)
+	require.Len(t, values, 5)
+	require.Equal(t, "servers.0.Value", jsonflag.JSONName(values[3].Path()))
+	require.Nil(t, values[3].Path()[1].Index)
+
+	require.NoError(t, values[3].Set("b"))
+	require.Equal(t, []TestStruct{{Value: "b"}}, given.Servers)
+
+	val := jsonflag.New(given)
+	require.NoError(t, val.SetPath([]string{"servers", "2", "Value"}, "c"))
+	require.Equal(t, []TestStruct{{Value: "b"}, {}, {Value: "c"}}, given.Servers)
+	require.NoError(t, val.SetPath([]string{"pointers", "0", "Value"}, "d"))
+	require.Equal(t, []*TestStruct{{Value: "d"}}, given.Pointers)
+
+	sub, err := val.Lookup("servers", "1")
+	require.NoError(t, err)
+	require.Equal(t, "servers.1", jsonflag.JSONName(sub.Path()))
+	require.NoError(t, sub.Set(`{"Value":"e"}`))
+	require.Equal(t, []TestStruct{{Value: "b"}, {Value: "e"}, {Value: "c"}}, given.Servers)
+
+	_, err = val.Lookup("servers", "-1")
+	require.ErrorIs(t, err, jsonflag.ErrUnknownPath)
+	require.EqualError(t, err, "jsonflag: unknown path: invalid slice index: servers.-1")
+}
+
 func TestZeroFlagValues(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,130 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errYAMLMergeValue = errors.New("merge value is not a mapping or a list of mappings")
+
+// DecodeYAML decodes YAML into the provided value, by converting it into JSON first, so that the value is populated using JSON names of struct fields (as package "encoding/json" would do). Timestamps are kept as strings in the YAML form. It can be used as decoder for flag values (see Value.SetDecoder and Value.SetJSONDecoder) and config files (see WithJSONDecoder).
+func DecodeYAML(data []byte, v any) error {
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	generic := any(nil)
+	if doc.Kind != 0 { // not empty input
+		x, err := yamlToJSON(&doc)
+		if err != nil {
+			return err
+		}
+		generic = x
+	}
+	b, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return unmarshalJSON(b, v)
+}
+
+// yamlToJSON converts YAML node into a value that can be encoded as JSON.
+func yamlToJSON(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil //nolint:nilnil // empty document is null
+		}
+		return yamlToJSON(n.Content[0])
+	case yaml.AliasNode:
+		return yamlToJSON(n.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, len(n.Content))
+		for i, c := range n.Content {
+			x, err := yamlToJSON(c)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = x
+		}
+		return list, nil
+	case yaml.MappingNode:
+		return yamlMappingToJSON(n)
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!str", "!!timestamp":
+			return n.Value, nil
+		}
+		x := any(nil)
+		if err := n.Decode(&x); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, fmt.Errorf("yaml: line %d: unsupported node", n.Line)
+}
+
+// yamlMappingToJSON converts YAML mapping node into a JSON object. Keys merged with '<<' are overridden by keys of the mapping.
+func yamlMappingToJSON(n *yaml.Node) (map[string]any, error) {
+	obj := map[string]any{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.ShortTag() != "!!merge" {
+			continue
+		}
+		merged := []*yaml.Node{v}
+		if v.Kind == yaml.SequenceNode {
+			merged = v.Content
+		}
+		for _, m := range merged {
+			if m.Kind == yaml.AliasNode {
+				m = m.Alias
+			}
+			if m.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("yaml: line %d: %w", m.Line, errYAMLMergeValue)
+			}
+			x, err := yamlMappingToJSON(m)
+			if err != nil {
+				return nil, err
+			}
+			for key, val := range x {
+				if _, ok := obj[key]; !ok {
+					obj[key] = val
+				}
+			}
+		}
+	}
+	explicit := map[string]any{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.ShortTag() == "!!merge" {
+			continue
+		}
+		x, err := yamlToJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		explicit[k.Value] = x
+	}
+	for key, val := range explicit {
+		obj[key] = val
+	}
+	return obj, nil
+}
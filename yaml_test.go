@@ -0,0 +1,110 @@
+// Copyright 2025 Marek Dalewski
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonflag_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/daishe/jsonflag"
+)
+
+type TestYAMLBase struct {
+	Host    string            `json:"host"`
+	Port    int               `json:"port"`
+	Date    string            `json:"date"`
+	Limits  map[string]any    `json:"limits"`
+	Servers []TestStruct      `json:"servers"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func TestDecodeYAML(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		given     string
+		want      *TestYAMLBase
+		wantError string
+	}{
+		{
+			name:  "empty",
+			given: "",
+			want:  &TestYAMLBase{},
+		},
+		{
+			name:  "json-names",
+			given: "host: example.com\nport: 8080\nservers:\n  - Value: a\n  - Value: b\n",
+			want:  &TestYAMLBase{Host: "example.com", Port: 8080, Servers: []TestStruct{{Value: "a"}, {Value: "b"}}},
+		},
+		{
+			name:  "scalars",
+			given: "date: 2025-01-02\nlimits: {cpu: 2, mem: 4Gi, big: 12345678901234567890, ratio: 0.5, hex: 0x1F, none: ~}\n",
+			want: &TestYAMLBase{Date: "2025-01-02", Limits: map[string]any{
+				"cpu": json.Number("2"), "mem": "4Gi", "big": json.Number("12345678901234567890"), "ratio": json.Number("0.5"), "hex": json.Number("31"), "none": nil,
+			}},
+		},
+		{
+			name:  "anchors-and-merge-keys",
+			given: "base: &base {a: x, b: y}\nlabels:\n  <<: *base\n  b: z\n",
+			want:  &TestYAMLBase{Labels: map[string]string{"a": "x", "b": "z"}},
+		},
+		{
+			name:      "syntax-error",
+			given:     "host: [a\n",
+			wantError: "yaml: line 1: did not find expected ',' or ']'",
+		},
+		{
+			name:      "type-error",
+			given:     "port: a\n",
+			wantError: "json: cannot unmarshal string into Go struct field TestYAMLBase.port of type int",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := &TestYAMLBase{}
+			err := jsonflag.DecodeYAML([]byte(test.given), got)
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestYAMLFlagValues(t *testing.T) {
+	t.Parallel()
+	given := &TestYAMLBase{}
+	values := jsonflag.Recursive(given, jsonflag.MaxDepth(1))
+	require.Len(t, values, 7)
+
+	values[0].SetDecoder(jsonflag.DecodeYAML)
+	require.NoError(t, values[0].Set("host: example.com"))
+	values[4].SetDecoder(jsonflag.DecodeYAML)
+	require.NoError(t, values[4].Set("{cpu: 2, mem: 4Gi}"))
+	values[6].SetJSONDecoder(jsonflag.DecodeYAML)
+	require.NoError(t, values[6].Set("{env: prod}"))
+	require.NoError(t, values[6].Set("team=core"))
+	require.Equal(t, &TestYAMLBase{
+		Host:   "example.com",
+		Limits: map[string]any{"cpu": json.Number("2"), "mem": "4Gi"},
+		Labels: map[string]string{"env": "prod", "team": "core"},
+	}, given)
+}